@@ -0,0 +1,189 @@
+package conust
+
+import (
+	"strings"
+)
+
+// Alphabet selects the characters that EncodeTokenAlphabet and EncodeMixedTextAlphabet write the tokens
+// with, for places where the bytewise order of the ASCII characters does not hold, or where some of them
+// are not welcome.
+type Alphabet int
+
+const (
+	// AlphabetDefault writes the tokens like EncodeToken, with the digits "0"..."9", "a"..."z", and "~"
+	// as the negative number terminator. Mixed text separates the tokens with spaces.
+	AlphabetDefault Alphabet = iota
+
+	// AlphabetCollation writes the tokens with lower case letters and digits only, and ends them in mixed
+	// text with "0", so the keys keep their order under database and locale collations that ignore
+	// case, accents, spaces and punctuation, like the en_US.UTF-8 collation of PostgreSQL or the _ci
+	// collations of MySQL, as well as under bytewise comparison. The digits "1"..."9" and "a"..."y"
+	// stand for the values up to 33, and "z1", "z2" and "z3" for the last digit values and the terminator.
+	AlphabetCollation
+
+	// AlphabetURL writes the tokens with the digits "0"..."9", "A"..."Z" and "_" as the terminator, and
+	// ends them in mixed text with "-", all of them characters that URLs never need to escape.
+	// The keys sort bytewise, and are case sensitive.
+	AlphabetURL
+
+	// AlphabetFilename writes the tokens like AlphabetCollation, and ends them in mixed text with "-". The
+	// characters are allowed on every common file system, and since there are no upper case letters, names
+	// cannot clash on case insensitive ones. The keys sort bytewise.
+	AlphabetFilename
+)
+
+// alphabetSymbolCount is the number of digit values of the tokens plus the negative number terminator.
+const alphabetSymbolCount = 37
+
+type alphabetSpec struct {
+	// symbols holds the characters written for the digit values in order, followed by the terminator.
+	// No symbol is the prefix of another one.
+	symbols   [alphabetSymbolCount]string
+	separator byte
+}
+
+var alphabetSpecs = [...]alphabetSpec{
+	AlphabetDefault: {
+		symbols:   alphabetSymbols("0123456789abcdefghijklmnopqrstuvwxyz~"),
+		separator: inTextSeparator,
+	},
+	AlphabetCollation: {
+		symbols:   alphabetSymbols("123456789abcdefghijklmnopqrstuvwxy", "z1", "z2", "z3"),
+		separator: '0',
+	},
+	AlphabetURL: {
+		symbols:   alphabetSymbols("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_"),
+		separator: '-',
+	},
+	AlphabetFilename: {
+		symbols:   alphabetSymbols("123456789abcdefghijklmnopqrstuvwxy", "z1", "z2", "z3"),
+		separator: '-',
+	},
+}
+
+func alphabetSymbols(singles string, escaped ...string) (symbols [alphabetSymbolCount]string) {
+	for i := 0; i < len(singles); i++ {
+		symbols[i] = singles[i : i+1]
+	}
+	copy(symbols[len(singles):], escaped)
+	return symbols
+}
+
+// EncodeTokenAlphabet turns the input number into a token like EncodeToken does, written with the
+// characters of the selected alphabet. The outputs sort by the value of the numbers under the comparison
+// the alphabet is meant for.
+func (c *Codec) EncodeTokenAlphabet(input string, alphabet Alphabet) (out string, ok bool) {
+	if alphabet < 0 || int(alphabet) >= len(alphabetSpecs) {
+		return "", false
+	}
+	token, ok := c.EncodeToken(input)
+	if !ok {
+		return "", false
+	}
+	return alphabetSpecs[alphabet].fromToken(token), true
+}
+
+// DecodeTokenAlphabet turns a string generated by EncodeTokenAlphabet with the same alphabet back into
+// the number.
+func (c *Codec) DecodeTokenAlphabet(input string, alphabet Alphabet) (out string, ok bool) {
+	if alphabet < 0 || int(alphabet) >= len(alphabetSpecs) {
+		return "", false
+	}
+	token, ok := alphabetSpecs[alphabet].toToken(input)
+	if !ok {
+		return "", false
+	}
+	return c.DecodeToken(token)
+}
+
+// EncodeMixedTextAlphabet is EncodeMixedText writing the tokens with the characters of the selected
+// alphabet. Except for AlphabetDefault, a token is not preceded by a separator, as its first character
+// already sorts before all letters, and it is followed by the separator of the alphabet instead of a space
+// to keep it from comparing against a longer token. So the separator never follows a bunch of ignored
+// punctuation, which would sort numbers separated by punctuation differently from the ones separated by
+// spaces under a collation. The text is kept as it is, so for the key to be safe in a URL or a file name,
+// the text has to be as well.
+func (c *Codec) EncodeMixedTextAlphabet(input string, alphabet Alphabet) (out string, ok bool) {
+	if alphabet < 0 || int(alphabet) >= len(alphabetSpecs) {
+		return "", false
+	}
+	if alphabet == AlphabetDefault {
+		return c.EncodeMixedText(input)
+	}
+	spec := &alphabetSpecs[alphabet]
+
+	var spans [][2]int
+	encoded, ok := c.encodeMixedText(input, func(start int, end int) {
+		spans = append(spans, [2]int{start, end})
+	})
+
+	var b strings.Builder
+	b.Grow(len(encoded) + len(encoded)/4)
+	textStart := 0
+	for _, span := range spans {
+		start, end := span[0], span[1]
+		if start > textStart && encoded[start-1] == inTextSeparator {
+			b.WriteString(encoded[textStart : start-1])
+		} else {
+			b.WriteString(encoded[textStart:start])
+		}
+		b.WriteString(spec.fromToken(encoded[start:end]))
+		textStart = end
+		if end < len(encoded) && encoded[end] == inTextSeparator {
+			b.WriteByte(spec.separator)
+			textStart++
+		}
+	}
+	b.WriteString(encoded[textStart:])
+	return b.String(), ok
+}
+
+// fromToken rewrites a token of EncodeToken with the symbols of the alphabet. Some recognizers write
+// more than one token, or a token followed by text like the unit of QuantityRecognizer, separated by
+// spaces: the spaces become the separator of the alphabet, and other bytes that are not digits are kept.
+func (a *alphabetSpec) fromToken(token string) string {
+	var b strings.Builder
+	b.Grow(len(token) + 2)
+	for i := 0; i < len(token); i++ {
+		switch ch := token[i]; {
+		case ch == negativeNumberTerminator:
+			b.WriteString(a.symbols[alphabetSymbolCount-1])
+		case isDigit(ch):
+			b.WriteString(a.symbols[digitToInt(ch)])
+		case ch == inTextSeparator:
+			b.WriteByte(a.separator)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// toToken rewrites the symbols of the alphabet back into a token of EncodeToken.
+func (a *alphabetSpec) toToken(input string) (token string, ok bool) {
+	var b strings.Builder
+	b.Grow(len(input))
+	for pos := 0; pos < len(input); {
+		value := a.symbolAt(input, pos)
+		if value < 0 {
+			return "", false
+		}
+		if value == alphabetSymbolCount-1 {
+			b.WriteByte(negativeNumberTerminator)
+		} else {
+			b.WriteByte(intToDigit(value))
+		}
+		pos += len(a.symbols[value])
+	}
+	return b.String(), true
+}
+
+// symbolAt returns the value of the symbol at the position of the input, or -1 if there is none.
+func (a *alphabetSpec) symbolAt(input string, pos int) int {
+	for value, symbol := range a.symbols {
+		if strings.HasPrefix(input[pos:], symbol) {
+			return value
+		}
+	}
+	return -1
+}
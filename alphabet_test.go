@@ -0,0 +1,233 @@
+package conust
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncodeTokenAlphabet(t *testing.T) {
+	testCases := []struct {
+		input    string
+		alphabet Alphabet
+		encoded  string
+	}{
+		{input: "12", alphabet: AlphabetDefault, encoded: "7212"},
+		{input: "-1", alphabet: AlphabetDefault, encoded: "3yy~"},
+		{input: "0", alphabet: AlphabetCollation, encoded: "6"},
+		{input: "12", alphabet: AlphabetCollation, encoded: "8323"},
+		{input: "-1", alphabet: AlphabetCollation, encoded: "4z1z1z3"},
+		{input: "zz", alphabet: AlphabetCollation, encoded: "83z2z2"},
+		{input: "-0.5", alphabet: AlphabetURL, encoded: "40U_"},
+		{input: "zz", alphabet: AlphabetURL, encoded: "72ZZ"},
+		{input: "-0.5", alphabet: AlphabetFilename, encoded: "51vz3"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.encoded, func(t *testing.T) {
+			encoded, ok := c.EncodeTokenAlphabet(i.input, i.alphabet)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeTokenAlphabet(encoded, i.alphabet)
+			if !ok || decoded != strings.TrimPrefix(i.input, "+") {
+				t.Fatalf("decoding expected %q got %q", i.input, decoded)
+			}
+		})
+	}
+
+	if _, ok := c.EncodeTokenAlphabet("1", Alphabet(10)); ok {
+		t.Fatal("encoding should have failed for an unknown alphabet")
+	}
+	for _, input := range []string{"80", "8z", "8z4", "83~"} {
+		if _, ok := c.DecodeTokenAlphabet(input, AlphabetCollation); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestEncodeMixedTextAlphabet(t *testing.T) {
+	testCases := []struct {
+		input    string
+		alphabet Alphabet
+		encoded  string
+	}{
+		{input: "Item 20b, -3 x1", alphabet: AlphabetDefault, encoded: "Item 722 b, - 713 x 711"},
+		{input: "Item 20b, -3 x1", alphabet: AlphabetCollation, encoded: "Item8330b, -8240x822"},
+		{input: "Item 20b, -3 x1", alphabet: AlphabetURL, encoded: "Item722-b, -713-x711"},
+		{input: "track 1.flac", alphabet: AlphabetFilename, encoded: "track822-.flac"},
+		{input: "1 2", alphabet: AlphabetFilename, encoded: "822-823"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.encoded, func(t *testing.T) {
+			encoded, ok := c.EncodeMixedTextAlphabet(i.input, i.alphabet)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+		})
+	}
+}
+
+func TestEncodeTokenAlphabet_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 20000; i++ {
+		a := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(4), 64)
+		b := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(4), 64)
+		if rand.Intn(4) == 0 {
+			a = randomString([]byte("0123456789abcxyz"), 5) + "1"
+		}
+		tokenA, _ := c.EncodeToken(a)
+		tokenB, _ := c.EncodeToken(b)
+		expected := compareStrings(tokenA, tokenB)
+
+		for alphabet := AlphabetDefault; alphabet <= AlphabetFilename; alphabet++ {
+			encodedA, _ := c.EncodeTokenAlphabet(a, alphabet)
+			encodedB, _ := c.EncodeTokenAlphabet(b, alphabet)
+			if got := compareStrings(encodedA, encodedB); got != expected {
+				t.Fatalf("%s and %s sort differently with alphabet %d (%q and %q)", a, b, alphabet, encodedA, encodedB)
+			}
+		}
+
+		encodedA, _ := c.EncodeTokenAlphabet(a, AlphabetCollation)
+		encodedB, _ := c.EncodeTokenAlphabet(b, AlphabetCollation)
+		if got := referenceCollationCompare(encodedA, encodedB); got != expected {
+			t.Fatalf("%s and %s collate differently (%q and %q)", a, b, encodedA, encodedB)
+		}
+	}
+}
+
+func TestEncodeMixedTextAlphabet_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 50000; i++ {
+		a := randomString([]byte("aBz -.,_01159"), 8)
+		b := randomString([]byte("aBz -.,_01159"), 8)
+		encodedA, _ := c.EncodeMixedTextAlphabet(a, AlphabetCollation)
+		encodedB, _ := c.EncodeMixedTextAlphabet(b, AlphabetCollation)
+		expected := compareNaturalElements(collationElements(a), collationElements(b), NumbersInText)
+		if expected == 0 {
+			continue
+		}
+		if got := referenceCollationCompare(encodedA, encodedB); got != expected {
+			t.Fatalf("%q and %q collate as %d, expected %d (keys %q and %q)", a, b, got, expected, encodedA, encodedB)
+		}
+	}
+
+	for _, alphabet := range []Alphabet{AlphabetURL, AlphabetFilename} {
+		for i := 0; i < 50000; i++ {
+			a := randomString([]byte("aBz._01159"), 8)
+			b := randomString([]byte("aBz._01159"), 8)
+			expected := compareNaturalElements(alphabetElements(a, '-'), alphabetElements(b, '-'), NumbersInText)
+			if expected == 0 {
+				continue
+			}
+			encodedA, _ := c.EncodeMixedTextAlphabet(a, alphabet)
+			encodedB, _ := c.EncodeMixedTextAlphabet(b, alphabet)
+			if got := compareStrings(encodedA, encodedB); got != expected {
+				t.Fatalf("%q and %q compare as %d with alphabet %d, expected %d (keys %q and %q)",
+					a, b, got, alphabet, expected, encodedA, encodedB)
+			}
+		}
+	}
+}
+
+func TestEncodeMixedTextAlphabet_Recognizers(t *testing.T) {
+	testCases := []struct {
+		recognizer Recognizer
+		ordered    []string
+	}{
+		{IPRecognizer{}, []string{"host 10.0.0.9", "host 10.0.0.10", "host ::1"}},
+		{DateRecognizer{}, []string{"1/5/2024", "1/10/2024", "Feb 1, 2024"}},
+		{QuantityRecognizer{}, []string{"5ms", "2s", "1min"}},
+		{FractionRecognizer{}, []string{"1/4", "3/8", "2 1/2"}},
+		{RomanRecognizer{}, []string{"Part IV", "Part IX", "Part XII"}},
+		{LiteralRecognizer{}, []string{"0x1F", "0x20", "1_000"}},
+		{CellRecognizer{}, []string{"B9", "B12", "AA3"}},
+	}
+
+	for _, i := range testCases {
+		c := &Codec{Recognizers: []Recognizer{i.recognizer}}
+		for alphabet := range alphabetSpecs {
+			prev := ""
+			for j, input := range i.ordered {
+				encoded, ok := c.EncodeMixedTextAlphabet(input, Alphabet(alphabet))
+				if !ok {
+					t.Fatalf("encoding failed for %q with alphabet %d", input, alphabet)
+				}
+				compare := compareStrings
+				if Alphabet(alphabet) == AlphabetCollation {
+					compare = referenceCollationCompare
+				}
+				if j > 0 && compare(prev, encoded) >= 0 {
+					t.Fatalf("%q does not sort before %q with alphabet %d", i.ordered[j-1], input, alphabet)
+				}
+				prev = encoded
+			}
+		}
+	}
+}
+
+// referenceCollationCompare emulates the primary strength of the usual locale collations on ASCII input:
+// spaces and punctuation are ignored, letters compare case insensitively, and digits come before letters.
+func referenceCollationCompare(a string, b string) int {
+	return compareStrings(collationKey(a), collationKey(b))
+}
+
+// alphabetElements splits the input into numbers and characters, with the separator after every number
+// not at the end, taking the place of one space already there.
+func alphabetElements(s string, separator byte) []naturalElement {
+	var elements []naturalElement
+	for _, element := range naturalElements(s, NumbersInText) {
+		n := len(elements)
+		switch {
+		case n > 0 && elements[n-1].number != nil && element.number == nil:
+			elements = append(elements, naturalElement{char: separator})
+			if element.char != ' ' {
+				elements = append(elements, element)
+			}
+		case element.number != nil && n > 0 && elements[n-1].char == ' ':
+			elements[n-1] = element
+		default:
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// collationElements splits the input like alphabetElements does, leaving out the characters the
+// collation ignores.
+func collationElements(s string) []naturalElement {
+	var elements []naturalElement
+	for _, element := range alphabetElements(s, '0') {
+		if element.number != nil {
+			elements = append(elements, element)
+		} else if key := collationKey(string(element.char)); key != "" {
+			elements = append(elements, naturalElement{char: key[0]})
+		}
+	}
+	return elements
+}
+
+func collationKey(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch >= 'A' && ch <= 'Z':
+			b.WriteByte(ch + 'a' - 'A')
+		case isASCIILetter(ch) || isDecimalDigit(ch):
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
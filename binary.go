@@ -0,0 +1,188 @@
+package conust
+
+import (
+	"strings"
+)
+
+// The significant digits of the binary format are packed three to a group of two bytes, as a number in
+// base 37: every digit takes one of 36 values, and the remaining value ends the digits.
+const binaryDigitBase = 37
+const binaryDigitsPerGroup = 3
+
+// binaryTerminator is the digit value that ends the digits of positive numbers, it sorts before all
+// digits. Negative numbers use binaryNegativeTerminator, which sorts after all digits, like the negative
+// number terminator of the text format.
+const binaryTerminator = 0
+const binaryNegativeTerminator = binaryDigitBase - 1
+
+// maxBinaryMagnitudeBytes limits the magnitude to 16777215 digits, so that decoding a malformed input
+// cannot allocate an arbitrary amount of memory.
+const maxBinaryMagnitudeBytes = 3
+
+// EncodeBinary turns the input number into a binary string that keeps the sign and magnitude semantics of
+// the Conust format, but packs three digits into two bytes. The outputs sort by the value of the numbers
+// under bytes.Compare, and no output is the prefix of another, so they can be concatenated into composite
+// keys without separators. Numbers with a magnitude above 16777215 digits cannot be encoded.
+func (c *Codec) EncodeBinary(input string) (out []byte, ok bool) {
+	token, ok := c.EncodeToken(input)
+	if !ok {
+		return nil, false
+	}
+	if token == "" || token == zeroOutput {
+		return []byte(token), true
+	}
+
+	positive, magnitudePositive, _ := c.decodeSigns(token)
+	magnitude, significantPartPos, _ := c.decodeMagnitude(token, positive, magnitudePositive)
+	if magnitude >= 1<<(8*maxBinaryMagnitudeBytes) {
+		return nil, false
+	}
+	digits := token[significantPartPos:]
+	if !positive {
+		digits = digits[:len(digits)-1]
+	}
+
+	out = make([]byte, 0, 2+maxBinaryMagnitudeBytes+(len(digits)/binaryDigitsPerGroup+1)*2)
+	out = append(out, token[0])
+	out = appendBinaryMagnitude(out, magnitude, positive != magnitudePositive)
+	return appendBinaryDigits(out, digits, positive), true
+}
+
+// DecodeBinary turns the number at the start of a string generated by EncodeBinary back into its normal
+// representation, and returns the rest of the input after it.
+func (c *Codec) DecodeBinary(input []byte) (out string, rest []byte, ok bool) {
+	if len(input) == 0 {
+		return "", input, true
+	}
+	if input[0] == zeroOutput[0] {
+		return zeroInput, input[1:], true
+	}
+
+	positive, magnitudePositive, ok := c.decodeSigns(string(input[:1]))
+	if !ok {
+		return "", nil, false
+	}
+	magnitude, rest, ok := readBinaryMagnitude(input[1:], positive != magnitudePositive)
+	if !ok {
+		return "", nil, false
+	}
+	digits, rest, ok := readBinaryDigits(rest, positive)
+	if !ok {
+		return "", nil, false
+	}
+
+	c.builder.Reset()
+	c.builder.WriteByte(input[0])
+	c.writeMagnitude(positive, magnitudePositive, magnitude)
+	c.builder.WriteString(digits)
+	if !positive {
+		c.builder.WriteByte(negativeNumberTerminator)
+	}
+	out, ok = c.DecodeToken(c.builder.String())
+	if !ok {
+		return "", nil, false
+	}
+	return out, rest, true
+}
+
+// appendBinaryMagnitude appends the magnitude as its length in bytes followed by its big endian bytes,
+// all of them inverted if reverse is set.
+func appendBinaryMagnitude(dst []byte, magnitude int, reverse bool) []byte {
+	var buf [8]byte
+	pos := len(buf)
+	for ; magnitude > 0; magnitude >>= 8 {
+		pos--
+		buf[pos] = byte(magnitude)
+	}
+
+	var mask byte
+	if reverse {
+		mask = 0xff
+	}
+	dst = append(dst, byte(len(buf)-pos)^mask)
+	for _, b := range buf[pos:] {
+		dst = append(dst, b^mask)
+	}
+	return dst
+}
+
+func readBinaryMagnitude(input []byte, reverse bool) (magnitude int, rest []byte, ok bool) {
+	var mask byte
+	if reverse {
+		mask = 0xff
+	}
+	if len(input) == 0 {
+		return 0, nil, false
+	}
+	length := int(input[0] ^ mask)
+	if length > maxBinaryMagnitudeBytes || len(input) < 1+length {
+		return 0, nil, false
+	}
+	for _, b := range input[1 : 1+length] {
+		magnitude = magnitude<<8 | int(b^mask)
+	}
+	if length > 0 && input[1]^mask == 0 {
+		return 0, nil, false
+	}
+	return magnitude, input[1+length:], true
+}
+
+// appendBinaryDigits packs the significant digits of a token, which are already inverted for negative
+// numbers, followed by the terminator.
+func appendBinaryDigits(dst []byte, digits string, positive bool) []byte {
+	offset, terminator := 1, binaryTerminator
+	if !positive {
+		offset, terminator = 0, binaryNegativeTerminator
+	}
+
+	for i := 0; i <= len(digits); i += binaryDigitsPerGroup {
+		group := 0
+		for j := i; j < i+binaryDigitsPerGroup; j++ {
+			value := terminator
+			if j < len(digits) {
+				value = digitToInt(digits[j]) + offset
+			}
+			group = group*binaryDigitBase + value
+		}
+		dst = append(dst, byte(group>>8), byte(group))
+	}
+	return dst
+}
+
+func readBinaryDigits(input []byte, positive bool) (digits string, rest []byte, ok bool) {
+	offset, terminator := 1, binaryTerminator
+	if !positive {
+		offset, terminator = 0, binaryNegativeTerminator
+	}
+
+	var b strings.Builder
+	var values [binaryDigitsPerGroup]int
+	for len(input) >= 2 {
+		group := int(input[0])<<8 | int(input[1])
+		input = input[2:]
+		for j := binaryDigitsPerGroup - 1; j >= 0; j-- {
+			values[j] = group % binaryDigitBase
+			group /= binaryDigitBase
+		}
+		if group != 0 {
+			return "", nil, false
+		}
+
+		for j, value := range values {
+			if value != terminator {
+				b.WriteByte(intToDigit(value - offset))
+				continue
+			}
+			for _, padding := range values[j:] {
+				if padding != terminator {
+					return "", nil, false
+				}
+			}
+			if b.Len() == 0 {
+				return "", nil, false
+			}
+			return b.String(), input, true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,132 @@
+package conust
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncodeBinary(t *testing.T) {
+	testCases := []struct {
+		input   string
+		encoded []byte
+		decoded string
+	}{
+		{input: "", encoded: []byte{}, decoded: ""},
+		{input: "0", encoded: []byte{'5'}, decoded: "0"},
+		{input: "1", encoded: []byte{'7', 0x01, 0x01, 0x0a, 0xb2}, decoded: "1"},
+		{input: "-1", encoded: []byte{'3', 0xfe, 0xfe, 0xbb, 0x2a}, decoded: "-1"},
+		{input: "123", encoded: []byte{'7', 0x01, 0x03, 0x0b, 0x25, 0x00, 0x00}, decoded: "123"},
+		{input: "0.00125", encoded: []byte{'6', 0xfe, 0xfd, 0x0b, 0x27, 0x00, 0x00}, decoded: "0.00125"},
+		{input: "-0.5", encoded: []byte{'4', 0x00, 0xa5, 0xc6}, decoded: "-0.5"},
+		{input: "zz", encoded: []byte{'7', 0x01, 0x02, 0xc5, 0xb8}, decoded: "zz"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.input, func(t *testing.T) {
+			encoded, ok := c.EncodeBinary(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if !bytes.Equal(encoded, i.encoded) {
+				t.Fatalf("encoding expected %#v got %#v", i.encoded, encoded)
+			}
+			decoded, rest, ok := c.DecodeBinary(encoded)
+			if !ok || decoded != i.decoded || len(rest) != 0 {
+				t.Fatalf("decoding expected %q got %q, %q, %v", i.decoded, decoded, rest, ok)
+			}
+		})
+	}
+}
+
+func TestEncodeBinary_HugeMagnitude(t *testing.T) {
+	c := new(Codec)
+	largest := "1" + strings.Repeat("0", 1<<24-2)
+	encoded, ok := c.EncodeBinary(largest)
+	if !ok {
+		t.Fatal("encoding failed for the largest magnitude")
+	}
+	if decoded, _, ok := c.DecodeBinary(encoded); !ok || decoded != largest {
+		t.Fatal("decoding failed for the largest magnitude")
+	}
+
+	for _, input := range []string{
+		"1" + strings.Repeat("0", 1<<24-1),
+		"-0." + strings.Repeat("0", 1<<24) + "1",
+	} {
+		if _, ok := c.EncodeBinary(input); ok {
+			t.Fatalf("encoding should have failed for a magnitude of %d digits", len(input))
+		}
+	}
+}
+
+func TestDecodeBinary_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range [][]byte{
+		{'8'},
+		{'7'},
+		{'7', 0x01},
+		{'7', 0x04, 0x01, 0x01, 0x01, 0x01, 0x0a, 0xb2},
+		{'7', 0x01, 0x00, 0x0a, 0xb2},
+		{'7', 0x01, 0x01, 0x0a},
+		{'7', 0x01, 0x01, 0x0a, 0xb3},
+		{'7', 0x01, 0x01, 0x00, 0x01},
+		{'7', 0x01, 0x01, 0xff, 0xff},
+		{'7', 0x01, 0x01, 0x00, 0x00},
+	} {
+		if _, _, ok := c.DecodeBinary(input); ok {
+			t.Fatalf("decoding should have failed for %#v", input)
+		}
+	}
+	if _, ok := c.EncodeBinary("1.2.3"); ok {
+		t.Fatal("encoding should have failed for \"1.2.3\"")
+	}
+}
+
+func TestEncodeBinary_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 50000; i++ {
+		a := randomBinaryTestNumber()
+		b := randomBinaryTestNumber()
+		tokenA, _ := c.EncodeToken(a)
+		tokenB, _ := c.EncodeToken(b)
+		binaryA, okA := c.EncodeBinary(a)
+		binaryB, okB := c.EncodeBinary(b)
+		if !okA || !okB {
+			t.Fatalf("encoding failed for %q or %q", a, b)
+		}
+		if compareStrings(tokenA, tokenB) != bytes.Compare(binaryA, binaryB) {
+			t.Fatalf("%q and %q give %#v and %#v", a, b, binaryA, binaryB)
+		}
+		if len(binaryA) < len(binaryB) && bytes.HasPrefix(binaryB, binaryA) {
+			t.Fatalf("%#v of %q is a prefix of %#v of %q", binaryA, a, binaryB, b)
+		}
+
+		composite := append(append([]byte(nil), binaryA...), binaryB...)
+		decodedA, rest, okA := c.DecodeBinary(composite)
+		decodedB, rest, okB := c.DecodeBinary(rest)
+		expectedA, _ := c.DecodeToken(tokenA)
+		expectedB, _ := c.DecodeToken(tokenB)
+		if !okA || !okB || decodedA != expectedA || decodedB != expectedB || len(rest) != 0 {
+			t.Fatalf("decoding %#v expected %q and %q got %q and %q", composite, expectedA, expectedB, decodedA, decodedB)
+		}
+	}
+}
+
+func randomBinaryTestNumber() string {
+	number := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(6), 64)
+	if rand.Intn(4) == 0 {
+		number = strconv.FormatFloat(rand.NormFloat64()*1e-20, 'f', -1, 64)
+	}
+	if rand.Intn(4) == 0 {
+		number = randomString([]byte("0123456789abcxyz"), 80)
+		if number == "" {
+			number = "0"
+		}
+	}
+	return number
+}
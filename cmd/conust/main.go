@@ -0,0 +1,103 @@
+// Command conust works with columns of Conust tokens.
+//
+// Usage:
+//
+//	conust migrate -from FORMAT -to FORMAT [-mark] < tokens > migrated
+//
+// The migrate subcommand reads tokens one per line, and writes them re-encoded in another format, checking
+// that every new token decodes to the same number as the original one. It stops at the first token that
+// cannot be migrated, reporting its line, and exits with a non-zero status. The formats are token, dense,
+// log, collation, url and filename. -from is required, and it also accepts auto for tokens that all carry
+// a format marker, which tells their format. With -mark the output tokens get format markers.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/koalamer/conust/v2"
+)
+
+const usage = "usage: conust migrate -from FORMAT -to FORMAT [-mark]"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "migrate":
+		return migrate(args[1:], stdin, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "conust: unknown command %q\n%s\n", args[0], usage)
+		return 2
+	}
+}
+
+func migrate(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fromName := flags.String("from", "", "format of the input tokens, or auto to read it from their format markers")
+	toName := flags.String("to", "", "format of the output tokens")
+	mark := flags.Bool("mark", false, "add format markers to the output tokens")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	detect := *fromName == "auto"
+	from, fromOk := conust.ParseFormat(*fromName)
+	to, toOk := conust.ParseFormat(*toName)
+	if (!fromOk && !detect) || !toOk || flags.NArg() > 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	c := new(conust.Codec)
+	out := bufio.NewWriter(stdout)
+	scanner := bufio.NewScanner(stdin)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		token := scanner.Text()
+		tokenFrom := from
+		if detect && token != "" {
+			var ok bool
+			if tokenFrom, ok = c.DetectFormat(token); !ok || !hasFormatMarker(token, tokenFrom) {
+				out.Flush()
+				fmt.Fprintf(stderr, "conust: line %d: %q has no valid format marker\n", line, token)
+				return 1
+			}
+		}
+
+		migrated, ok := c.Migrate(token, tokenFrom, to)
+		if !ok {
+			out.Flush()
+			fmt.Fprintf(stderr, "conust: line %d: cannot migrate %q from %s to %s\n", line, token, tokenFrom, to)
+			return 1
+		}
+		if *mark && !hasFormatMarker(token, tokenFrom) {
+			migrated = conust.AddFormatMarker(migrated, to)
+		}
+		fmt.Fprintln(out, migrated)
+	}
+	if err := scanner.Err(); err != nil {
+		out.Flush()
+		fmt.Fprintf(stderr, "conust: %v\n", err)
+		return 1
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintf(stderr, "conust: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func hasFormatMarker(token string, format conust.Format) bool {
+	return token != "" && conust.AddFormatMarker(token[1:], format) == token
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		args   []string
+		input  string
+		output string
+		status int
+	}{
+		{name: "auto", args: []string{"migrate", "-from", "auto", "-to", "dense"}, input: "t711\nt3yy~\n\nc8323\n", output: "dE\"+\ndAyz~\n\ndE#-\n"},
+		{name: "auto without marker", args: []string{"migrate", "-from", "auto", "-to", "dense"}, input: "t711\n7z26\n", output: "dE\"+\n", status: 1},
+		{name: "no from", args: []string{"migrate", "-to", "dense"}, input: "7z26\n", status: 2},
+		{name: "mark", args: []string{"migrate", "-from", "token", "-to", "collation", "-mark"}, input: "711\nt7212\n", output: "c822\nc8323\n"},
+		{name: "failure", args: []string{"migrate", "-from", "token", "-to", "dense"}, input: "711\n72zz\n7212\n", output: "E\"+\n", status: 1},
+		{name: "malformed", args: []string{"migrate", "-from", "token", "-to", "dense"}, input: "711\n30~\n", output: "E\"+\n", status: 1},
+		{name: "no format", args: []string{"migrate", "-from", "token"}, status: 2},
+		{name: "unknown format", args: []string{"migrate", "-from", "hex", "-to", "dense"}, status: 2},
+		{name: "unknown command", args: []string{"convert"}, status: 2},
+		{name: "no command", status: 2},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(i.args, strings.NewReader(i.input), &stdout, &stderr)
+			if status != i.status {
+				t.Fatalf("status expected %d got %d (%s)", i.status, status, stderr.String())
+			}
+			if stdout.String() != i.output {
+				t.Fatalf("output expected %q got %q", i.output, stdout.String())
+			}
+			if status != 0 && stderr.Len() == 0 {
+				t.Fatal("no error message")
+			}
+		})
+	}
+}
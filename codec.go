@@ -11,7 +11,17 @@ import (
 // There is also EncodeMixedText, a convenience function, that encodes each group of decimal numbers
 // and returns the resulting string. So that for example the strings "Item 20" and "Item 100" become
 // "Item 722" and "Item 731" which sort as the numeric value in them would naturally imply.
+//
+// The zero value is ready to use. Setting Recognizers makes EncodeMixedText encode more than groups of
+// decimal digits, like IP addresses, by their value.
 type Codec struct {
+	// Recognizers are tried in order by EncodeMixedText at every position outside of a number,
+	// the first one to recognize a value wins.
+	Recognizers []Recognizer
+	// NumberPlacement decides whether EncodeMixedText sorts numbers where they fall among the text
+	// characters, which is the default, or before or after all text.
+	NumberPlacement NumberPlacement
+
 	builder strings.Builder
 }
 
@@ -59,12 +69,17 @@ func (c *Codec) EncodeToken(input string) (out string, ok bool) {
 }
 
 // DecodeToken turns a Conust string back into its normal representation. The output will not reconstruct
-// leading and trailing zeros. The plus sign for positive numbers is omitted as well.
+// leading and trailing zeros, unless the token was generated by EncodeTokenScale, which records the number
+// of fractional digits. The plus sign for positive numbers is omitted as well.
 func (c *Codec) DecodeToken(input string) (out string, ok bool) {
 	if input == "" {
 		return "", true
 	}
 
+	if pos := strings.IndexByte(input, scaleSeparator); pos >= 0 {
+		return c.decodeScaledToken(input[:pos], input[pos+1:])
+	}
+
 	if input == zeroOutput {
 		return zeroInput, true
 	}
@@ -93,6 +108,9 @@ func (c *Codec) DecodeToken(input string) (out string, ok bool) {
 	}
 
 	significantPartLength := encodedLength - sStartPos
+	if significantPartLength <= 0 {
+		return "", false
+	}
 
 	for i := sStartPos; i < encodedLength; i++ {
 		if !isDigit(input[i]) {
@@ -130,57 +148,88 @@ func (c *Codec) DecodeToken(input string) (out string, ok bool) {
 }
 
 // EncodeMixedText is a convinience function that replaces all groups of decimal numbers of the input
-// with Conust strings also surrounding them with spaces (if not already present) to ensure the expected ordering
+// with Conust strings also surrounding them with spaces (if not already present) to ensure the expected ordering.
+// Values found by the Recognizers of the codec are replaced the same way.
+// When the NumberPlacement of the codec is NumbersFirst or NumbersLast, tokens are preceded by a marker byte
+// instead of a space, and ASCII punctuation in the text is prefixed with "!" to group it before letters.
+//
+// The keys sort as if every number was a separate word compared by value: a single space next to a number
+// is part of its separator, so "A300Z" and "A 300 Z" give the same key, and every other byte of the text,
+// including tabs and other control characters, keeps its place in the byte order.
 func (c *Codec) EncodeMixedText(input string) (out string, ok bool) {
-	insideNumber := false
-	donePartEnd := 0
+	return c.encodeMixedText(input, nil)
+}
+
+// encodeMixedText implements EncodeMixedText, calling onToken (if not nil) with the position of every
+// token in the output.
+func (c *Codec) encodeMixedText(input string, onToken func(start int, end int)) (out string, ok bool) {
 	var b strings.Builder
 	ok = true
 	b.Grow(len(input) + 6)
 
-	for i := 0; i < len(input); i++ {
-		if input[i] >= digit0 && input[i] <= digit9 {
-			if !insideNumber {
-				b.Write([]byte(input[donePartEnd:i]))
-				donePartEnd = i
-				insideNumber = true
-				if i > 0 && input[i-1] != inTextSeparator {
-					b.WriteByte(inTextSeparator)
-				}
-			}
+	textStart := 0
+	for i := 0; i < len(input); {
+		end, encoded, encOk := c.matchToken(input, i)
+		if end == i {
+			i++
 			continue
 		}
-		if insideNumber {
-			encoded, encOk := c.EncodeToken(input[donePartEnd:i])
-			if encOk {
-				b.WriteString(encoded)
-			} else {
-				b.WriteString(input[donePartEnd:i])
-				ok = false
-			}
-			insideNumber = false
-			donePartEnd = i
-			if input[i] != inTextSeparator {
+
+		if marker, placed := c.NumberPlacement.tokenMarker(); placed {
+			writePlacedText(&b, input[textStart:i])
+			b.WriteByte(marker)
+		} else {
+			b.WriteString(input[textStart:i])
+			if i > 0 && input[i-1] != inTextSeparator {
 				b.WriteByte(inTextSeparator)
 			}
 		}
-	}
-	if !insideNumber {
-		b.WriteString(input[donePartEnd:])
-	} else {
-		encoded, encOk := c.EncodeToken(input[donePartEnd:])
 		if encOk {
+			if onToken != nil {
+				onToken(b.Len(), b.Len()+len(encoded))
+			}
 			b.WriteString(encoded)
 		} else {
-			b.WriteString(input[donePartEnd:])
+			b.WriteString(input[i:end])
 			ok = false
 		}
+		if end < len(input) && input[end] != inTextSeparator {
+			b.WriteByte(inTextSeparator)
+		}
+		i = end
+		textStart = end
+	}
+	if _, placed := c.NumberPlacement.tokenMarker(); placed {
+		writePlacedText(&b, input[textStart:])
+	} else {
+		b.WriteString(input[textStart:])
 	}
 
 	out = b.String()
 	return
 }
 
+// scanDecimalRuns splits the input into maximal runs of decimal digits and runs of anything else,
+// and calls segment for each of them in order.
+func scanDecimalRuns(input string, segment func(start int, end int, number bool)) {
+	runStart := 0
+	insideNumber := false
+	for i := 0; i < len(input); i++ {
+		digit := isDecimalDigit(input[i])
+		if digit == insideNumber {
+			continue
+		}
+		if i > runStart {
+			segment(runStart, i, insideNumber)
+		}
+		runStart = i
+		insideNumber = digit
+	}
+	if runStart < len(input) {
+		segment(runStart, len(input), insideNumber)
+	}
+}
+
 func (c *Codec) isValidInput(input string) bool {
 	if !isSignByte(input[0]) && !isDigit(input[0]) {
 		return false
@@ -318,6 +367,9 @@ func (c *Codec) decodeMagnitude(in string, positive bool, magnitudePositive bool
 	reverseDigits := positive != magnitudePositive
 	var digitValue int
 	for i := 1; i < len(in); i++ {
+		if !isDigit(in[i]) {
+			return 0, 0, false
+		}
 		if reverseDigits {
 			digitValue = reversedDigitToInt(in[i])
 		} else {
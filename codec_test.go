@@ -2,7 +2,9 @@ package conust
 
 import (
 	"fmt"
+	"math/big"
 	"math/rand"
+	"regexp"
 	"strconv"
 	"testing"
 )
@@ -175,6 +177,8 @@ func TestCodec_DecodeToken_Failure(t *testing.T) {
 		{name: "no negative terminator", input: "40zx"},
 		{name: "non digit char", input: "7z412X"},
 		{name: "bad prefix", input: "2z412"},
+		{name: "terminator in magnitude", input: "30~"},
+		{name: "no significant digits", input: "3z~"},
 	}
 
 	codec := new(Codec)
@@ -254,6 +258,12 @@ func TestEncodeMixedText(t *testing.T) {
 		{name: "mixed c2", input: "SomeCam600D", ok: true, output: "SomeCam 736 D"},
 		{name: "mixed c3", input: "SomeCam1000D", ok: true, output: "SomeCam 741 D"},
 		{name: "mixed c4", input: "SomeCam1100D", ok: true, output: "SomeCam 7411 D"},
+		{name: "tab before", input: "A\t300", ok: true, output: "A\t 733"},
+		{name: "tab after", input: "300\tZ", ok: true, output: "733 \tZ"},
+		{name: "space and tab", input: "A \t300\t Z", ok: true, output: "A \t 733 \t Z"},
+		{name: "newlines", input: "A\n300\n", ok: true, output: "A\n 733 \n"},
+		{name: "tilde", input: "~300~", ok: true, output: "~ 733 ~"},
+		{name: "tilde and space", input: "~ 300 ~", ok: true, output: "~ 733 ~"},
 	}
 	c := new(Codec)
 	for _, i := range testCases {
@@ -271,6 +281,114 @@ func TestEncodeMixedText(t *testing.T) {
 	}
 }
 
+func TestEncodeMixedText_Order(t *testing.T) {
+	testMixedTextOrder(t, new(Codec))
+}
+
+// testMixedTextOrder checks the order of the keys generated by the codec against referenceNaturalCompare
+// on random inputs made of digits, separators, control bytes and bytes that are not valid UTF-8.
+func testMixedTextOrder(t *testing.T, c *Codec) {
+	alphabet := []byte{
+		0x00, 0x01, 0x02, '\t', '\n', 0x1f, ' ', ' ', '!', '(', '/', ':', 'A', 'a', 'z', '~', 0x7f, 0x80, 0xc3, 0xfe, 0xff,
+		'0', '1', '1', '5', '9',
+	}
+
+	rand.Seed(42)
+	for i := 0; i < 100000; i++ {
+		a := randomString(alphabet, 8)
+		b := randomString(alphabet, 8)
+		expected := referenceNaturalCompare(a, b, c.NumberPlacement)
+		if expected == 0 {
+			continue
+		}
+		encodedA, _ := c.EncodeMixedText(a)
+		encodedB, _ := c.EncodeMixedText(b)
+		if got := compareStrings(encodedA, encodedB); got != expected {
+			t.Fatalf("%q and %q compare as %d, expected %d (keys %q and %q)", a, b, got, expected, encodedA, encodedB)
+		}
+	}
+}
+
+type naturalElement struct {
+	number *big.Int
+	char   byte
+}
+
+// referenceNaturalCompare compares the inputs following the documentation of EncodeMixedText: every group
+// of decimal digits is a separate word compared by value, so a space is added between a number and any
+// byte next to it other than a space. With NumbersInText a number compares to text like a digit character.
+// With the other placements a number is below or above all text, only the space after it is added, and
+// ASCII punctuation sorts between space and letters.
+func referenceNaturalCompare(a string, b string, placement NumberPlacement) int {
+	return compareNaturalElements(naturalElements(a, placement), naturalElements(b, placement), placement)
+}
+
+var (
+	naturalSpaceAfter  = regexp.MustCompile("([0-9])([^0-9 ])")
+	naturalSpaceBefore = regexp.MustCompile("([^0-9 ])([0-9])")
+	naturalWord        = regexp.MustCompile("[0-9]+|[^0-9]+")
+)
+
+func compareNaturalElements(x []naturalElement, y []naturalElement, placement NumberPlacement) int {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		var result int
+		switch {
+		case x[i].number != nil && y[i].number != nil:
+			result = x[i].number.Cmp(y[i].number)
+		case x[i].number == nil && y[i].number == nil:
+			result = compareNaturalChars(x[i].char, y[i].char, placement)
+		case x[i].number != nil:
+			result = compareNaturalNumberToChar(y[i].char, placement)
+		default:
+			result = -compareNaturalNumberToChar(x[i].char, placement)
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return sign(len(x) - len(y))
+}
+
+func naturalElements(input string, placement NumberPlacement) []naturalElement {
+	input = naturalSpaceAfter.ReplaceAllString(input, "$1 $2")
+	if placement == NumbersInText {
+		input = naturalSpaceBefore.ReplaceAllString(input, "$1 $2")
+	}
+
+	var elements []naturalElement
+	for _, word := range naturalWord.FindAllString(input, -1) {
+		if value, ok := new(big.Int).SetString(word, 10); ok {
+			elements = append(elements, naturalElement{number: value})
+			continue
+		}
+		for i := 0; i < len(word); i++ {
+			elements = append(elements, naturalElement{char: word[i]})
+		}
+	}
+	return elements
+}
+
+func compareNaturalChars(x byte, y byte, placement NumberPlacement) int {
+	if placement != NumbersInText && isASCIIPunctuation(x) != isASCIIPunctuation(y) {
+		if isASCIIPunctuation(x) {
+			x = punctuationMarker
+		} else {
+			y = punctuationMarker
+		}
+	}
+	return sign(int(x) - int(y))
+}
+
+func compareNaturalNumberToChar(char byte, placement NumberPlacement) int {
+	switch placement {
+	case NumbersFirst:
+		return -1
+	case NumbersLast:
+		return 1
+	}
+	return sign('5' - int(char))
+}
+
 func BenchmarkEncodeMixedText(b *testing.B) {
 	var charPool = [...]byte{
 		'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j',
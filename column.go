@@ -0,0 +1,123 @@
+package conust
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxColumnLabelLength keeps the value of a column label within an int64.
+const maxColumnLabelLength = 13
+
+const defaultCellColumnLetters = 3
+const absoluteReferenceMarker byte = '$'
+
+// EncodeColumn turns a spreadsheet column label like "A", "Z", "AA" or "XFD" into the Conust token of
+// its value. The labels are bijective base 26 numbers (A is 1, Z is 26, AA is 27), which have no zero
+// digit, so they cannot be encoded as base 36 numbers by EncodeToken. The label is case insensitive and
+// may have up to 13 letters.
+func (c *Codec) EncodeColumn(label string) (out string, ok bool) {
+	value, ok := columnValue(label)
+	if !ok {
+		return "", false
+	}
+	return c.EncodeToken(strconv.FormatInt(value, 10))
+}
+
+// DecodeColumn turns a string generated by EncodeColumn back into the upper case column label.
+func (c *Codec) DecodeColumn(input string) (label string, ok bool) {
+	decoded, ok := c.DecodeToken(input)
+	if !ok {
+		return "", false
+	}
+	value, err := strconv.ParseInt(decoded, 10, 64)
+	if err != nil || value < 1 {
+		return "", false
+	}
+
+	var buf [maxColumnLabelLength + 1]byte
+	pos := len(buf)
+	for value > 0 {
+		value--
+		pos--
+		buf[pos] = byte('A' + value%26)
+		value /= 26
+	}
+	return string(buf[pos:]), true
+}
+
+func columnValue(label string) (value int64, ok bool) {
+	if label == "" || len(label) > maxColumnLabelLength {
+		return 0, false
+	}
+	for i := 0; i < len(label); i++ {
+		ch := toLowerASCII(label[i])
+		if ch < 'a' || ch > 'z' {
+			return 0, false
+		}
+		value = value*26 + int64(ch-'a'+1)
+	}
+	return value, true
+}
+
+// CellRecognizer recognizes spreadsheet cell references like "B12", "AA3" or "$C$7" in mixed text,
+// and encodes them as the token of the column value followed by a space and the token of the row,
+// so "Z9" sorts before "AA3", and "B9" before "B12". The "$" markers of absolute references are ignored.
+//
+// The column must be written in upper case, unless IgnoreCase is set, and the reference must be a whole word.
+type CellRecognizer struct {
+	// MaxLetters is the maximum length of the column label, 3 if not set, which covers column XFD,
+	// the last one of most spreadsheet programs.
+	MaxLetters int
+	// IgnoreCase also recognizes lower case column labels.
+	IgnoreCase bool
+}
+
+// Recognize implements the Recognizer interface.
+func (r CellRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	if pos > 0 && (isWordByte(input[pos-1]) || input[pos-1] == absoluteReferenceMarker) {
+		return 0, "", false
+	}
+
+	maxLetters := r.MaxLetters
+	if maxLetters <= 0 {
+		maxLetters = defaultCellColumnLetters
+	}
+
+	end := pos
+	if input[end] == absoluteReferenceMarker {
+		end++
+	}
+	columnStart := end
+	for end < len(input) && ((input[end] >= 'A' && input[end] <= 'Z') || (r.IgnoreCase && input[end] >= 'a' && input[end] <= 'z')) {
+		end++
+	}
+	column := input[columnStart:end]
+	if column == "" || len(column) > maxLetters {
+		return 0, "", false
+	}
+
+	if end < len(input) && input[end] == absoluteReferenceMarker {
+		end++
+	}
+	rowStart := end
+	for end < len(input) && isDecimalDigit(input[end]) {
+		end++
+	}
+	row := input[rowStart:end]
+	if row == "" || row[0] == digit0 || (end < len(input) && isWordByte(input[end])) {
+		return 0, "", false
+	}
+
+	columnToken, ok := c.EncodeColumn(column)
+	if !ok {
+		return 0, "", false
+	}
+	rowToken, _ := c.EncodeToken(row)
+
+	var b strings.Builder
+	b.Grow(len(columnToken) + len(rowToken) + 1)
+	b.WriteString(columnToken)
+	b.WriteByte(inTextSeparator)
+	b.WriteString(rowToken)
+	return end - pos, b.String(), true
+}
@@ -0,0 +1,125 @@
+package conust
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEncodeColumn(t *testing.T) {
+	testCases := []struct {
+		label   string
+		value   string
+		decoded string
+	}{
+		{label: "A", value: "1", decoded: "A"},
+		{label: "Z", value: "26", decoded: "Z"},
+		{label: "AA", value: "27", decoded: "AA"},
+		{label: "az", value: "52", decoded: "AZ"},
+		{label: "ZZ", value: "702", decoded: "ZZ"},
+		{label: "AAA", value: "703", decoded: "AAA"},
+		{label: "XFD", value: "16384", decoded: "XFD"},
+		{label: "ZZZZZZZZZZZZZ", value: "2580398988131886038", decoded: "ZZZZZZZZZZZZZ"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.label, func(t *testing.T) {
+			encoded, ok := c.EncodeColumn(i.label)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.label)
+			}
+			expected, _ := c.EncodeToken(i.value)
+			if encoded != expected {
+				t.Fatalf("encoding expected %q got %q", expected, encoded)
+			}
+			decoded, ok := c.DecodeColumn(encoded)
+			if !ok || decoded != i.decoded {
+				t.Fatalf("decoding expected %q got %q", i.decoded, decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeColumn_Order(t *testing.T) {
+	c := new(Codec)
+	prev := LessThanAny
+	for value := 1; value < 20000; value++ {
+		label, ok := c.DecodeColumn(mustEncodeToken(c, value))
+		if !ok {
+			t.Fatalf("decoding failed for %d", value)
+		}
+		encoded, ok := c.EncodeColumn(label)
+		if !ok {
+			t.Fatalf("encoding failed for %q", label)
+		}
+		if prev >= encoded {
+			t.Fatalf("%q does not sort after the previous label", label)
+		}
+		prev = encoded
+	}
+}
+
+func mustEncodeToken(c *Codec, value int) string {
+	encoded, _ := c.EncodeToken(strconv.Itoa(value))
+	return encoded
+}
+
+func TestEncodeColumn_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []string{"", "A1", "Ä", "AAAAAAAAAAAAAA", "-A"} {
+		if _, ok := c.EncodeColumn(input); ok {
+			t.Fatalf("encoding should have failed for %q", input)
+		}
+	}
+	for _, input := range []string{"5", "3yy~", "7115", "71a", "X"} {
+		if _, ok := c.DecodeColumn(input); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestCellRecognizer(t *testing.T) {
+	testCases := []struct {
+		name       string
+		recognizer CellRecognizer
+		input      string
+		output     string
+	}{
+		{name: "cell", input: "B12", output: "712 7212"},
+		{name: "in text", input: "map AA3 to name", output: "map 7227 713 to name"},
+		{name: "absolute", input: "=$C$7", output: "= 713 717"},
+		{name: "lower case", input: "b12", output: "b 7212"},
+		{name: "ignore case", recognizer: CellRecognizer{IgnoreCase: true}, input: "b12", output: "712 7212"},
+		{name: "too many letters", input: "ABCD1", output: "ABCD 711"},
+		{name: "more letters allowed", recognizer: CellRecognizer{MaxLetters: 4}, input: "ABCD1", output: "751901 711"},
+		{name: "row zero", input: "A0", output: "A 5"},
+		{name: "inside a word", input: "xA1 A1b", output: "xA 711 A 711 b"},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			c := &Codec{Recognizers: []Recognizer{i.recognizer}}
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.output {
+				t.Fatalf("output expected %q got %q", i.output, encoded)
+			}
+		})
+	}
+}
+
+func TestCellRecognizer_Order(t *testing.T) {
+	ordered := []string{"A1", "A2", "A10", "B1", "B9", "B12", "Z9", "AA3", "AB1", "AZ100", "BA1", "XFD1"}
+
+	c := &Codec{Recognizers: []Recognizer{CellRecognizer{}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
@@ -72,6 +72,10 @@ func isDigit(digit byte) bool {
 		(digit >= digitA && digit <= digitZ)
 }
 
+func isDecimalDigit(digit byte) bool {
+	return digit >= digit0 && digit <= digit9
+}
+
 func digitToInt(digit byte) int {
 	if digit < digitA {
 		return int(digit - digit0)
@@ -0,0 +1,253 @@
+package conust
+
+import (
+	"strings"
+	"time"
+)
+
+// DateOrder tells how to read numeric dates that do not start with the year.
+type DateOrder int
+
+const (
+	// MonthDayYear reads "1/5/2024" as January 5, the common US order.
+	MonthDayYear DateOrder = iota
+	// DayMonthYear reads "5.1.2024" as January 5, the common European order.
+	DayMonthYear
+)
+
+// dateTokenPrefix starts the tokens of DateRecognizer. It sorts after the sign bytes of all numbers, so
+// dates form their own class in mixed text, and no date compares equal to a number like its Unix time.
+const dateTokenPrefix byte = '9'
+
+var monthNames = [...]string{
+	"january", "february", "march", "april", "may", "june",
+	"july", "august", "september", "october", "november", "december",
+}
+
+// DateRecognizer recognizes calendar dates in mixed text and encodes them with EncodeTime as midnight UTC
+// of the day, so that dates written in any of the recognized formats sort chronologically.
+//
+// Numeric dates have a four digit year and use "/", "." or "-" consistently as the separator. They are
+// read as year-month-day when they start with the year ("2024-01-05"), and according to Order otherwise.
+// Dates with English month names, full or abbreviated, can be written month first ("Jan 5, 2024",
+// "January 5th 2024") or day first ("5 Jan 2024"). Text that is not a valid date is left alone.
+//
+// The token of a date is prefixed with "9", so dates sort after all numbers, "2024" before "Jan 1, 1970".
+type DateRecognizer struct {
+	Order DateOrder
+}
+
+// Recognize implements the Recognizer interface.
+func (r DateRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	if pos > 0 && isWordByte(input[pos-1]) {
+		return 0, "", false
+	}
+	// the end of a longer series of numbers
+	if pos > 1 && isDateSeparator(input[pos-1]) && isDecimalDigit(input[pos-2]) {
+		return 0, "", false
+	}
+
+	var date time.Time
+	var end int
+	if isDecimalDigit(input[pos]) {
+		date, end, ok = r.parseNumericDate(input, pos)
+		if !ok {
+			date, end, ok = parseDayFirstDate(input, pos)
+		}
+	} else {
+		date, end, ok = parseMonthFirstDate(input, pos)
+	}
+	if !ok {
+		return 0, "", false
+	}
+	return end - pos, string(dateTokenPrefix) + c.EncodeTime(date), true
+}
+
+func (r DateRecognizer) parseNumericDate(input string, pos int) (date time.Time, end int, ok bool) {
+	var parts [3]int
+	var digitCounts [3]int
+	var separator byte
+	end = pos
+	for i := range parts {
+		if i > 0 {
+			if end >= len(input) || (i == 1 && !isDateSeparator(input[end])) || (i == 2 && input[end] != separator) {
+				return time.Time{}, 0, false
+			}
+			separator = input[end]
+			end++
+		}
+		parts[i], digitCounts[i], end = parseSmallNumber(input, end, 4)
+		if digitCounts[i] == 0 {
+			return time.Time{}, 0, false
+		}
+	}
+	if !isDateEnd(input, end, separator) {
+		return time.Time{}, 0, false
+	}
+
+	var year, month, day int
+	switch {
+	case digitCounts[0] == 4 && digitCounts[1] <= 2 && digitCounts[2] <= 2:
+		year, month, day = parts[0], parts[1], parts[2]
+	case digitCounts[2] == 4 && digitCounts[0] <= 2 && digitCounts[1] <= 2:
+		year = parts[2]
+		if r.Order == DayMonthYear {
+			day, month = parts[0], parts[1]
+		} else {
+			month, day = parts[0], parts[1]
+		}
+	default:
+		return time.Time{}, 0, false
+	}
+
+	date, ok = makeDate(year, month, day)
+	return date, end, ok
+}
+
+// parseMonthFirstDate reads dates like "Jan 5, 2024" or "January 5th 2024".
+func parseMonthFirstDate(input string, pos int) (date time.Time, end int, ok bool) {
+	month, end, ok := parseMonthName(input, pos)
+	if !ok || end >= len(input) || input[end] != ' ' {
+		return time.Time{}, 0, false
+	}
+	end = skipSpaces(input, end)
+
+	day, dayDigits, end := parseSmallNumber(input, end, 2)
+	if dayDigits == 0 {
+		return time.Time{}, 0, false
+	}
+	end = skipOrdinalSuffix(input, end)
+	if end < len(input) && input[end] == ',' {
+		end++
+	}
+	if end >= len(input) || input[end] != ' ' {
+		return time.Time{}, 0, false
+	}
+	end = skipSpaces(input, end)
+
+	year, yearDigits, end := parseSmallNumber(input, end, 4)
+	if yearDigits != 4 || !isDateEnd(input, end, 0) {
+		return time.Time{}, 0, false
+	}
+
+	date, ok = makeDate(year, month, day)
+	return date, end, ok
+}
+
+// parseDayFirstDate reads dates like "5 Jan 2024" or "5th January, 2024".
+func parseDayFirstDate(input string, pos int) (date time.Time, end int, ok bool) {
+	day, dayDigits, end := parseSmallNumber(input, pos, 2)
+	if dayDigits == 0 {
+		return time.Time{}, 0, false
+	}
+	end = skipOrdinalSuffix(input, end)
+	if end >= len(input) || input[end] != ' ' {
+		return time.Time{}, 0, false
+	}
+	end = skipSpaces(input, end)
+
+	month, end, ok := parseMonthName(input, end)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	if end < len(input) && input[end] == ',' {
+		end++
+	}
+	if end >= len(input) || input[end] != ' ' {
+		return time.Time{}, 0, false
+	}
+	end = skipSpaces(input, end)
+
+	year, yearDigits, end := parseSmallNumber(input, end, 4)
+	if yearDigits != 4 || !isDateEnd(input, end, 0) {
+		return time.Time{}, 0, false
+	}
+
+	date, ok = makeDate(year, month, day)
+	return date, end, ok
+}
+
+// parseMonthName reads a full or abbreviated English month name, case insensitively. An abbreviation
+// can be followed by a dot.
+func parseMonthName(input string, pos int) (month int, end int, ok bool) {
+	end = pos
+	for end < len(input) && isASCIILetter(input[end]) {
+		end++
+	}
+	word := strings.ToLower(input[pos:end])
+	if len(word) < 3 {
+		return 0, 0, false
+	}
+
+	for i, name := range monthNames {
+		if word == name {
+			return i + 1, end, true
+		}
+		if strings.HasPrefix(name, word) && (len(word) == 3 || word == "sept") {
+			if end < len(input) && input[end] == '.' {
+				end++
+			}
+			return i + 1, end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// parseSmallNumber reads at most maxDigits decimal digits. If there are more digits it reads none.
+func parseSmallNumber(input string, pos int, maxDigits int) (value int, digitCount int, end int) {
+	end = pos
+	for end < len(input) && isDecimalDigit(input[end]) {
+		if end-pos == maxDigits {
+			return 0, 0, pos
+		}
+		value = value*10 + int(input[end]-digit0)
+		end++
+	}
+	return value, end - pos, end
+}
+
+func skipSpaces(input string, pos int) int {
+	for pos < len(input) && input[pos] == ' ' {
+		pos++
+	}
+	return pos
+}
+
+func skipOrdinalSuffix(input string, pos int) int {
+	if pos+2 > len(input) {
+		return pos
+	}
+	switch strings.ToLower(input[pos : pos+2]) {
+	case "st", "nd", "rd", "th":
+		if pos+2 == len(input) || !isWordByte(input[pos+2]) {
+			return pos + 2
+		}
+	}
+	return pos
+}
+
+func isDateSeparator(ch byte) bool {
+	return ch == '/' || ch == '.' || ch == '-'
+}
+
+// isDateEnd checks that the date is not followed by more of something that looks like a date or a number.
+func isDateEnd(input string, pos int, separator byte) bool {
+	if pos == len(input) {
+		return true
+	}
+	if isWordByte(input[pos]) {
+		return false
+	}
+	return separator == 0 || input[pos] != separator || pos+1 == len(input) || !isDecimalDigit(input[pos+1])
+}
+
+func makeDate(year int, month int, day int) (time.Time, bool) {
+	if month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -0,0 +1,114 @@
+package conust
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateRecognizer(t *testing.T) {
+	testCases := []struct {
+		name  string
+		order DateOrder
+		input string
+		date  time.Time
+		rest  string
+	}{
+		{name: "iso", input: "2024-01-05", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "year first slashes", input: "2024/1/5", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "us", input: "1/5/2024", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "european", order: DayMonthYear, input: "5.1.2024", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "european dashes", order: DayMonthYear, input: "25-12-1999", date: time.Date(1999, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{name: "month name", input: "Jan 5, 2024", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "full month name", input: "January 5th 2024", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "abbreviation with dot", input: "Sept. 30, 1850", date: time.Date(1850, 9, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "day first", input: "5 Jan 2024", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "day first ordinal", input: "21st MARCH, 2021", date: time.Date(2021, 3, 21, 0, 0, 0, 0, time.UTC)},
+		{name: "sentence end", input: "2024-01-05.", date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), rest: " ."},
+		{name: "leap day", input: "29.2.2024", order: DayMonthYear, date: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			c := &Codec{Recognizers: []Recognizer{DateRecognizer{Order: i.order}}}
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if expected := string(dateTokenPrefix) + c.EncodeTime(i.date) + i.rest; encoded != expected {
+				t.Fatalf("output expected %q got %q", expected, encoded)
+			}
+		})
+	}
+}
+
+func TestDateRecognizer_NotDates(t *testing.T) {
+	testCases := []string{
+		"2024",
+		"1/5",
+		"2/30/2024",
+		"13/1/2024",
+		"1/5-2024",
+		"1/5/24",
+		"1.2.3.2024",
+		"v1/5/2024",
+		"2024-01-05x",
+		"Jan 2024",
+		"Janx 5, 2024",
+		"Ja 5, 2024",
+		"5 Jan 24",
+	}
+
+	plain := new(Codec)
+	c := &Codec{Recognizers: []Recognizer{DateRecognizer{}}}
+	for _, input := range testCases {
+		expected, _ := plain.EncodeMixedText(input)
+		encoded, _ := c.EncodeMixedText(input)
+		if encoded != expected {
+			t.Fatalf("%q should not contain a date, got %q", input, encoded)
+		}
+	}
+}
+
+func TestDateRecognizer_Order(t *testing.T) {
+	ordered := []string{
+		"report 12/31/1969",
+		"report Jan 1, 1970",
+		"report 1/5/2024",
+		"report 2024-01-06",
+		"report 7 Jan 2024",
+		"report 1/10/2024",
+		"report February 1st, 2024",
+		"report 11/2/2024",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{DateRecognizer{Order: MonthDayYear}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
+
+func TestDateRecognizer_PlainNumbers(t *testing.T) {
+	ordered := []string{
+		"0",
+		"2024",
+		"1000000000",
+		"12/31/1969",
+		"Jan 1, 1970",
+		"1/5/2024",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{DateRecognizer{}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
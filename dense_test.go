@@ -0,0 +1,81 @@
+package conust
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestEncodeDense(t *testing.T) {
+	testCases := []struct {
+		input   string
+		encoded string
+		decoded string
+	}{
+		{input: "", encoded: "", decoded: ""},
+		{input: "0", encoded: "C", decoded: "0"},
+		{input: "1", encoded: "E\"+", decoded: "1"},
+		{input: "-1", encoded: "Ayz~", decoded: "-1"},
+		{input: "123", encoded: "E$-?", decoded: "123"},
+		{input: "-123", encoded: "Awxf~", decoded: "-123"},
+		{input: "1200", encoded: "E%-", decoded: "1200"},
+		{input: "0.00125", encoded: "Dx-S", decoded: "0.00125"},
+		{input: "-0.5", encoded: "B!R~", decoded: "-0.5"},
+		{input: "1999", encoded: "E%4{*", decoded: "1999"},
+		{input: "+9.9", encoded: "E\"{*", decoded: "9.9"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.input, func(t *testing.T) {
+			encoded, ok := c.EncodeDense(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeDense(encoded)
+			if !ok || decoded != i.decoded {
+				t.Fatalf("decoding expected %q got %q", i.decoded, decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeDense_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []string{"a", "1e5", "--1", "1.2.3"} {
+		if _, ok := c.EncodeDense(input); ok {
+			t.Fatalf("encoding should have failed for %q", input)
+		}
+	}
+	for _, input := range []string{"7", "E", "E\"", "E\"|", "E\"{", "E\"{+", "Ayz", "C!", "E\" +"} {
+		if _, ok := c.DecodeDense(input); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestEncodeDense_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 20000; i++ {
+		a := strconv.FormatFloat(rand.NormFloat64()*1e6, 'f', rand.Intn(8), 64)
+		b := strconv.FormatFloat(rand.NormFloat64()*1e6, 'f', rand.Intn(8), 64)
+		tokenA, _ := c.EncodeToken(a)
+		tokenB, _ := c.EncodeToken(b)
+		denseA, ok := c.EncodeDense(a)
+		if !ok {
+			t.Fatalf("encoding failed for %q", a)
+		}
+		denseB, _ := c.EncodeDense(b)
+		if compareStrings(tokenA, tokenB) != compareStrings(denseA, denseB) {
+			t.Fatalf("%s and %s sort differently (%q and %q)", a, b, denseA, denseB)
+		}
+		expected, _ := c.DecodeToken(tokenA)
+		if decoded, _ := c.DecodeDense(denseA); decoded != expected {
+			t.Fatalf("decoding expected %q got %q", expected, decoded)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package conust
+
+// descendingTerminator follows the terminator of descending strings.
+const descendingTerminator byte = 0x01
+
+// EncodeTokenDesc turns the input number into a Conust string that sorts in descending numeric order, so
+// that the highest number comes first. It is the token of the negated number: the digits are inverted the
+// same way EncodeToken does for negative numbers, and the terminator of negative numbers keeps "12" after
+// "120". The output is only meant to be compared with other outputs of EncodeTokenDesc.
+func (c *Codec) EncodeTokenDesc(input string) (out string, ok bool) {
+	if input == "" {
+		return "", true
+	}
+	if !c.isValidInput(input) {
+		return "", false
+	}
+	return c.EncodeToken(negateNumber(input))
+}
+
+// DecodeTokenDesc turns a string generated by EncodeTokenDesc back into the number.
+func (c *Codec) DecodeTokenDesc(input string) (out string, ok bool) {
+	decoded, ok := c.DecodeToken(input)
+	if !ok || decoded == "" || decoded == zeroInput {
+		return decoded, ok
+	}
+	return negateNumber(decoded), true
+}
+
+// EncodeMixedTextDesc works like EncodeMixedText, but the outputs sort in the reverse order. Each byte of
+// the key is inverted, after escaping zero bytes and terminating the key so that a key sorts after the
+// longer keys starting with it. The output is binary and it cannot be decoded.
+func (c *Codec) EncodeMixedTextDesc(input string) (out string, ok bool) {
+	ascending, ok := c.EncodeMixedText(input)
+	return string(appendDescending(make([]byte, 0, len(ascending)+1), ascending)), ok
+}
+
+// negateNumber flips the sign of a number in the format EncodeToken accepts.
+func negateNumber(number string) string {
+	switch number[0] {
+	case minusByte:
+		return number[1:]
+	case plusByte:
+		return string(minusByte) + number[1:]
+	}
+	return string(minusByte) + number
+}
+
+// appendDescending appends the inverted bytes of the input, with zero bytes escaped and a terminator at
+// the end, which makes the result sort in the reverse order of the inputs. The terminator is two bytes
+// long, so that no result is the prefix of another one.
+func appendDescending(dst []byte, input string) []byte {
+	for i := 0; i < len(input); i++ {
+		dst = append(dst, ^input[i])
+		if input[i] == tupleTerminator {
+			dst = append(dst, ^tupleEscapedZero)
+		}
+	}
+	return append(dst, ^tupleTerminator, ^descendingTerminator)
+}
@@ -0,0 +1,86 @@
+package conust
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestEncodeTokenDesc(t *testing.T) {
+	testCases := []struct {
+		input   string
+		encoded string
+		decoded string
+	}{
+		{input: "", encoded: "", decoded: ""},
+		{input: "0", encoded: "5", decoded: "0"},
+		{input: "12", encoded: "3xyx~", decoded: "12"},
+		{input: "120", encoded: "3wyx~", decoded: "120"},
+		{input: "+1.5", encoded: "3yyu~", decoded: "1.5"},
+		{input: "-12", encoded: "7212", decoded: "-12"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.input, func(t *testing.T) {
+			encoded, ok := c.EncodeTokenDesc(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeTokenDesc(encoded)
+			if !ok || decoded != i.decoded {
+				t.Fatalf("decoding expected %q got %q", i.decoded, decoded)
+			}
+		})
+	}
+
+	if _, ok := c.EncodeTokenDesc("--1"); ok {
+		t.Fatal("encoding should have failed for \"--1\"")
+	}
+}
+
+func TestEncodeTokenDesc_Order(t *testing.T) {
+	c := new(Codec)
+	ordered := []string{"1000", "120", "12", "1.5", "1", "0.01", "0", "-0.01", "-1", "-12", "-120"}
+	prev, _ := c.EncodeTokenDesc(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeTokenDesc(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+
+	rand.Seed(42)
+	for i := 0; i < 20000; i++ {
+		a := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(4), 64)
+		b := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(4), 64)
+		ascendingA, _ := c.EncodeToken(a)
+		ascendingB, _ := c.EncodeToken(b)
+		descendingA, _ := c.EncodeTokenDesc(a)
+		descendingB, _ := c.EncodeTokenDesc(b)
+		if compareStrings(ascendingA, ascendingB) != -compareStrings(descendingA, descendingB) {
+			t.Fatalf("%s and %s do not sort in reverse order", a, b)
+		}
+	}
+}
+
+func TestEncodeMixedTextDesc_Order(t *testing.T) {
+	alphabet := []byte{0x00, 0x01, ' ', 'a', 'b', 0xff, '0', '1', '9'}
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 50000; i++ {
+		a := randomString(alphabet, 6)
+		b := randomString(alphabet, 6)
+		ascendingA, _ := c.EncodeMixedText(a)
+		ascendingB, _ := c.EncodeMixedText(b)
+		descendingA, _ := c.EncodeMixedTextDesc(a)
+		descendingB, _ := c.EncodeMixedTextDesc(b)
+		if compareStrings(ascendingA, ascendingB) != -compareStrings(descendingA, descendingB) {
+			t.Fatalf("%q and %q do not sort in reverse order (keys %q and %q)", a, b, descendingA, descendingB)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package conust
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DirOptions configures the ordering of directory listings. The zero value sorts entries by the
+// EncodeMixedText version of their names. Names that are still equal, like "a01" and "a1", are ordered
+// bytewise.
+type DirOptions struct {
+	// DirsFirst lists directories before every other kind of entry.
+	DirsFirst bool
+	// FoldCase compares names case insensitively, using the original name only to break ties.
+	FoldCase bool
+}
+
+const dirTieBreak byte = 0x00
+const dirClassDirectory byte = 0x01
+const dirClassOther byte = 0x02
+
+// ReadDirNatural reads the named directory and returns its entries in natural order, so that
+// "file9" comes before "file10".
+func ReadDirNatural(fsys fs.FS, dir string) ([]fs.DirEntry, error) {
+	return DirOptions{}.ReadDir(fsys, dir)
+}
+
+// WalkDirNatural walks the file tree rooted at root like fs.WalkDir does, but visits the entries
+// of each directory in natural order.
+func WalkDirNatural(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	return DirOptions{}.WalkDir(fsys, root, fn)
+}
+
+// ReadDir reads the named directory and returns its entries ordered according to the options.
+// Like fs.ReadDir, it returns the entries read before an error occurred along with the error.
+func (o DirOptions) ReadDir(fsys fs.FS, dir string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	o.sortEntries(entries)
+	return entries, err
+}
+
+// WalkDir walks the file tree rooted at root like fs.WalkDir does, but visits the entries
+// of each directory in the order defined by the options.
+func (o DirOptions) WalkDir(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fs.Stat(fsys, root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = o.walkDir(fsys, root, statDirEntry{info}, fn)
+	}
+	if err == fs.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func (o DirOptions) walkDir(fsys fs.FS, name string, d fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(name, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := o.ReadDir(fsys, name)
+	if err != nil {
+		err = fn(name, d, err)
+		if err != nil {
+			if err == fs.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if err := o.walkDir(fsys, path.Join(name, entry.Name()), entry, fn); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (o DirOptions) sortEntries(entries []fs.DirEntry) {
+	c := new(Codec)
+	keyed := keyedEntries{entries: entries, keys: make([]string, len(entries))}
+	for i, entry := range entries {
+		keyed.keys[i] = o.entryKey(c, entry)
+	}
+	sort.Sort(keyed)
+}
+
+func (o DirOptions) entryKey(c *Codec, entry fs.DirEntry) string {
+	var b strings.Builder
+	name := entry.Name()
+	if o.DirsFirst {
+		if entry.IsDir() {
+			b.WriteByte(dirClassDirectory)
+		} else {
+			b.WriteByte(dirClassOther)
+		}
+	}
+	if o.FoldCase {
+		encoded, _ := c.EncodeMixedText(strings.ToLower(name))
+		b.WriteString(encoded)
+		b.WriteByte(dirTieBreak)
+	}
+	encoded, _ := c.EncodeMixedText(name)
+	b.WriteString(encoded)
+	// Names like "a01" and "a1" have the same encoded form, the name itself keeps their order stable.
+	b.WriteByte(dirTieBreak)
+	b.WriteString(name)
+	return b.String()
+}
+
+type keyedEntries struct {
+	entries []fs.DirEntry
+	keys    []string
+}
+
+func (k keyedEntries) Len() int           { return len(k.entries) }
+func (k keyedEntries) Less(i, j int) bool { return k.keys[i] < k.keys[j] }
+func (k keyedEntries) Swap(i, j int) {
+	k.entries[i], k.entries[j] = k.entries[j], k.entries[i]
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+}
+
+// statDirEntry presents the FileInfo of the walk root as a DirEntry.
+type statDirEntry struct {
+	info fs.FileInfo
+}
+
+func (d statDirEntry) Name() string               { return d.info.Name() }
+func (d statDirEntry) IsDir() bool                { return d.info.IsDir() }
+func (d statDirEntry) Type() fs.FileMode          { return d.info.Mode().Type() }
+func (d statDirEntry) Info() (fs.FileInfo, error) { return d.info, nil }
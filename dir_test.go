@@ -0,0 +1,151 @@
+package conust
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"site/page10.md":          {},
+		"site/page9.md":           {},
+		"site/Page2.md":           {},
+		"site/assets/img10.png":   {},
+		"site/assets/img9.png":    {},
+		"site/drafts2/b.md":       {},
+		"site/drafts10/a.md":      {},
+		"site/Zebra.md":           {},
+		"site/drafts2/notes1.txt": {},
+	}
+}
+
+func entryNames(entries []fs.DirEntry) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names
+}
+
+func TestReadDirNatural(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     DirOptions
+		expected []string
+	}{
+		{
+			name:     "default",
+			expected: []string{"Page2.md", "Zebra.md", "assets", "drafts2", "drafts10", "page9.md", "page10.md"},
+		},
+		{
+			name:     "dirs first",
+			opts:     DirOptions{DirsFirst: true},
+			expected: []string{"assets", "drafts2", "drafts10", "Page2.md", "Zebra.md", "page9.md", "page10.md"},
+		},
+		{
+			name:     "fold case",
+			opts:     DirOptions{FoldCase: true},
+			expected: []string{"assets", "drafts2", "drafts10", "Page2.md", "page9.md", "page10.md", "Zebra.md"},
+		},
+	}
+
+	fsys := testFS()
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			entries, err := i.opts.ReadDir(fsys, "site")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := entryNames(entries); !reflect.DeepEqual(names, i.expected) {
+				t.Fatalf("expected %q got %q", i.expected, names)
+			}
+		})
+	}
+
+	entries, err := ReadDirNatural(fsys, "site/assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := entryNames(entries); !reflect.DeepEqual(names, []string{"img9.png", "img10.png"}) {
+		t.Fatalf("unexpected order %q", names)
+	}
+
+	ties := fstest.MapFS{"a1": {}, "a01": {}, "A": {}, "a": {}, "a001": {}}
+	for _, opts := range []DirOptions{{}, {FoldCase: true}} {
+		entries, err := opts.ReadDir(ties, ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < len(entries)/2; j++ {
+			entries[j], entries[len(entries)-1-j] = entries[len(entries)-1-j], entries[j]
+		}
+		opts.sortEntries(entries)
+		if names := entryNames(entries); !reflect.DeepEqual(names, []string{"A", "a", "a001", "a01", "a1"}) {
+			t.Fatalf("equal names are not ordered bytewise with %+v: %q", opts, names)
+		}
+	}
+
+	if _, err := ReadDirNatural(fsys, "missing"); err == nil {
+		t.Fatal("reading a missing directory should fail")
+	}
+}
+
+func TestWalkDirNatural(t *testing.T) {
+	fsys := testFS()
+
+	var visited []string
+	err := DirOptions{DirsFirst: true}.WalkDir(fsys, "site", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == "assets" {
+			return fs.SkipDir
+		}
+		visited = append(visited, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"site",
+		"site/drafts2",
+		"site/drafts2/b.md",
+		"site/drafts2/notes1.txt",
+		"site/drafts10",
+		"site/drafts10/a.md",
+		"site/Page2.md",
+		"site/Zebra.md",
+		"site/page9.md",
+		"site/page10.md",
+	}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Fatalf("expected %q got %q", expected, visited)
+	}
+
+	errStop := errors.New("stop")
+	visited = nil
+	err = WalkDirNatural(fsys, "site/assets", func(p string, d fs.DirEntry, err error) error {
+		visited = append(visited, p)
+		if len(visited) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+	if !reflect.DeepEqual(visited, []string{"site/assets", "site/assets/img9.png"}) {
+		t.Fatalf("unexpected walk %q", visited)
+	}
+
+	err = WalkDirNatural(fsys, "missing", func(p string, d fs.DirEntry, err error) error {
+		return err
+	})
+	if err == nil {
+		t.Fatal("walking a missing root should fail")
+	}
+}
@@ -0,0 +1,132 @@
+package conust
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FilenameOptions configures EncodeFilename. The zero value splits off every extension, sorts extensions
+// by their mixed text encoding, and keeps the case of the name.
+type FilenameOptions struct {
+	// MaxExtensions limits how many trailing extensions are separated from the stem, zero means no limit.
+	MaxExtensions int
+	// ExtensionOrder lists extensions (without the dot) that sort before every other extension,
+	// in the order given here.
+	ExtensionOrder []string
+	// HiddenFirst places dotfiles before every other name.
+	HiddenFirst bool
+	// FoldCase compares names case insensitively, using the original name only to break ties.
+	FoldCase bool
+}
+
+const filenameTieBreak byte = 0x00
+const filenameExtensionSeparator byte = 0x01
+const filenameRankedExtension byte = 0x02
+const filenameOtherExtension byte = 0x03
+
+const filenameClassHidden byte = 0x01
+const filenameClassRegular byte = 0x02
+
+// EncodeFilename turns a file name into a sort key that compares the stem of the name first, and the
+// extensions only when the stems are equal. So "file9.txt.bak" sorts before "file10.txt", and with
+// "png" listed in ExtensionOrder "img9.png" < "img10.png" < "img10.jpeg".
+//
+// Extensions are the trailing dot separated alphanumeric segments that contain a letter, so
+// "archive.tar.gz" has the stem "archive" while "report.2024.10.pdf" has the stem "report.2024.10".
+// The leading dot of a dotfile belongs to its stem.
+// The stem and the extensions are encoded with EncodeMixedText. The output is a sort key only,
+// it cannot be decoded.
+func (c *Codec) EncodeFilename(name string, opts FilenameOptions) (out string, ok bool) {
+	if name == "" {
+		return "", true
+	}
+
+	original := name
+	if opts.FoldCase {
+		name = strings.ToLower(name)
+	}
+	stem, extensions := splitFilename(name, opts.MaxExtensions)
+
+	var b strings.Builder
+	b.Grow(2*len(name) + 8)
+	ok = true
+
+	if opts.HiddenFirst {
+		if name[0] == '.' {
+			b.WriteByte(filenameClassHidden)
+		} else {
+			b.WriteByte(filenameClassRegular)
+		}
+	}
+
+	encoded, encOk := c.EncodeMixedText(stem)
+	ok = ok && encOk
+	b.WriteString(encoded)
+	b.WriteByte(filenameTieBreak)
+
+	for i, extension := range extensions {
+		if i > 0 {
+			b.WriteByte(filenameExtensionSeparator)
+		}
+		if rank := extensionRank(extension, opts); rank >= 0 {
+			token, _ := c.EncodeToken(strconv.Itoa(rank))
+			b.WriteByte(filenameRankedExtension)
+			b.WriteString(token)
+			continue
+		}
+		encoded, encOk := c.EncodeMixedText(extension)
+		ok = ok && encOk
+		b.WriteByte(filenameOtherExtension)
+		b.WriteString(encoded)
+	}
+
+	if opts.FoldCase {
+		b.WriteByte(filenameTieBreak)
+		b.WriteString(original)
+	}
+
+	out = b.String()
+	return
+}
+
+// splitFilename separates the trailing extensions from the name. The first character always belongs to
+// the stem, which keeps dotfiles intact.
+func splitFilename(name string, maxExtensions int) (stem string, extensions []string) {
+	stemEnd := len(name)
+	for maxExtensions <= 0 || len(extensions) < maxExtensions {
+		dot := strings.LastIndexByte(name[:stemEnd], '.')
+		if dot < 1 || !isExtension(name[dot+1:stemEnd]) {
+			break
+		}
+		extensions = append(extensions, name[dot+1:stemEnd])
+		stemEnd = dot
+	}
+
+	for i, j := 0, len(extensions)-1; i < j; i, j = i+1, j-1 {
+		extensions[i], extensions[j] = extensions[j], extensions[i]
+	}
+	return name[:stemEnd], extensions
+}
+
+func isExtension(segment string) bool {
+	hasLetter := false
+	for i := 0; i < len(segment); i++ {
+		switch {
+		case isASCIILetter(segment[i]):
+			hasLetter = true
+		case isDecimalDigit(segment[i]):
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+func extensionRank(extension string, opts FilenameOptions) int {
+	for i, ranked := range opts.ExtensionOrder {
+		if extension == ranked || (opts.FoldCase && strings.EqualFold(extension, ranked)) {
+			return i
+		}
+	}
+	return -1
+}
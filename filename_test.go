@@ -0,0 +1,97 @@
+package conust
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitFilename(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		maxExtensions int
+		stem          string
+		extensions    []string
+	}{
+		{name: "no extension", input: "README", stem: "README"},
+		{name: "single", input: "img10.png", stem: "img10", extensions: []string{"png"}},
+		{name: "double", input: "archive.tar.gz", stem: "archive", extensions: []string{"tar", "gz"}},
+		{name: "limited", input: "archive.tar.gz", maxExtensions: 1, stem: "archive.tar", extensions: []string{"gz"}},
+		{name: "numeric segments", input: "report.2024.10.pdf", stem: "report.2024.10", extensions: []string{"pdf"}},
+		{name: "digits in extension", input: "song.mp3", stem: "song", extensions: []string{"mp3"}},
+		{name: "dotfile", input: ".bashrc", stem: ".bashrc"},
+		{name: "dotfile with extension", input: ".config.json", stem: ".config", extensions: []string{"json"}},
+		{name: "trailing dot", input: "file.", stem: "file."},
+		{name: "version", input: "1.0.tar.gz", stem: "1.0", extensions: []string{"tar", "gz"}},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			stem, extensions := splitFilename(i.input, i.maxExtensions)
+			if stem != i.stem {
+				t.Fatalf("stem expected %q got %q", i.stem, stem)
+			}
+			if len(extensions) != 0 || len(i.extensions) != 0 {
+				if !reflect.DeepEqual(extensions, i.extensions) {
+					t.Fatalf("extensions expected %q got %q", i.extensions, extensions)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeFilename_Order(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    FilenameOptions
+		ordered []string
+	}{
+		{
+			name:    "stem before extension",
+			ordered: []string{"file9.txt", "file9.txt.bak", "file10.txt"},
+		},
+		{
+			name:    "extensions alphabetically",
+			ordered: []string{"img9.png", "img10.jpeg", "img10.png", "img10.png.bak"},
+		},
+		{
+			name:    "ranked extensions",
+			opts:    FilenameOptions{ExtensionOrder: []string{"png"}},
+			ordered: []string{"img9.png", "img10.png", "img10.jpeg"},
+		},
+		{
+			name:    "dotted stems",
+			ordered: []string{"report.2024.9.pdf", "report.2024.10.pdf", "report.2025.1.pdf"},
+		},
+		{
+			name:    "hidden first",
+			opts:    FilenameOptions{HiddenFirst: true},
+			ordered: []string{".bashrc", ".config.json", "Makefile", "a.txt"},
+		},
+		{
+			name:    "fold case",
+			opts:    FilenameOptions{FoldCase: true, ExtensionOrder: []string{"PNG"}},
+			ordered: []string{"A1.png", "a1.PNG", "a1.png", "a1.jpeg", "B2.txt", "b10.txt"},
+		},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			prev, ok := c.EncodeFilename(i.ordered[0], i.opts)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.ordered[0])
+			}
+			for j := 1; j < len(i.ordered); j++ {
+				encoded, ok := c.EncodeFilename(i.ordered[j], i.opts)
+				if !ok {
+					t.Fatalf("encoding failed for %q", i.ordered[j])
+				}
+				if prev >= encoded {
+					t.Fatalf("%q does not sort before %q", i.ordered[j-1], i.ordered[j])
+				}
+				prev = encoded
+			}
+		})
+	}
+}
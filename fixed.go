@@ -0,0 +1,90 @@
+package conust
+
+import (
+	"strings"
+)
+
+// EncodeFixed turns the input number into a token of exactly width bytes, for CHAR(n) columns and fixed
+// size keys. Positive numbers and zero are padded with "0" digits, which do not change their value.
+// Negative numbers drop their terminator and are padded with "z", the inverted "0" digit, which sorts
+// after all other digits, just like the terminator would. So the padded tokens sort like the ones of
+// EncodeToken. Encoding fails if the token does not fit into width bytes, use FixedWidth to find the
+// width the numbers of a column need. An empty input has no fixed width token, and fails as well.
+func (c *Codec) EncodeFixed(input string, width int) (out string, ok bool) {
+	if input == "" {
+		return "", false
+	}
+	token, ok := c.EncodeToken(input)
+	if !ok {
+		return "", false
+	}
+
+	padding := digit0
+	if token[len(token)-1] == negativeNumberTerminator {
+		token = token[:len(token)-1]
+		padding = reverseDigit(digit0)
+	}
+	if len(token) > width {
+		return "", false
+	}
+	if len(token) == width {
+		return token, true
+	}
+
+	var b strings.Builder
+	b.Grow(width)
+	b.WriteString(token)
+	for i := len(token); i < width; i++ {
+		b.WriteByte(padding)
+	}
+	return b.String(), true
+}
+
+// DecodeFixed turns a token generated by EncodeFixed back into the number.
+func (c *Codec) DecodeFixed(input string) (out string, ok bool) {
+	if input == "" {
+		return "", false
+	}
+	if input[0] == zeroOutput[0] {
+		if strings.Trim(input[1:], string(digit0)) != "" {
+			return "", false
+		}
+		return zeroInput, true
+	}
+
+	positive, magnitudePositive, ok := c.decodeSigns(input)
+	if !ok {
+		return "", false
+	}
+	_, significantPartPos, ok := c.decodeMagnitude(input, positive, magnitudePositive)
+	if !ok {
+		return "", false
+	}
+
+	padding := digit0
+	if !positive {
+		padding = reverseDigit(digit0)
+	}
+	end := len(input)
+	for end > significantPartPos && input[end-1] == padding {
+		end--
+	}
+	if positive {
+		return c.DecodeToken(input[:end])
+	}
+	return c.DecodeToken(input[:end] + string(negativeNumberTerminator))
+}
+
+// FixedWidth returns the width EncodeFixed needs for any number with at most intDigits digits before the
+// decimal point and fracDigits digits after it.
+func FixedWidth(intDigits int, fracDigits int) int {
+	magnitude := intDigits
+	if fracDigits > magnitude {
+		magnitude = fracDigits
+	}
+	magnitudeLength := 1
+	if magnitude > maxMagnitudeDigitValue {
+		magnitudeLength = (magnitude + maxMagnitudeDigitValue - 1) / maxMagnitudeDigitValue
+	}
+	return 1 + magnitudeLength + intDigits + fracDigits
+}
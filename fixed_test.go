@@ -0,0 +1,113 @@
+package conust
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEncodeFixed(t *testing.T) {
+	testCases := []struct {
+		input   string
+		width   int
+		encoded string
+		decoded string
+	}{
+		{input: "0", width: 1, encoded: "5", decoded: "0"},
+		{input: "0", width: 4, encoded: "5000", decoded: "0"},
+		{input: "10", width: 6, encoded: "721000", decoded: "10"},
+		{input: "+1.5", width: 4, encoded: "7115", decoded: "1.5"},
+		{input: "-1.5", width: 4, encoded: "3yyu", decoded: "-1.5"},
+		{input: "-10", width: 6, encoded: "3xyzzz", decoded: "-10"},
+		{input: "0.05", width: 5, encoded: "6y500", decoded: "0.05"},
+		{input: "-0.05", width: 5, encoded: "41uzz", decoded: "-0.05"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.input, func(t *testing.T) {
+			encoded, ok := c.EncodeFixed(i.input, i.width)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeFixed(encoded)
+			if !ok || decoded != i.decoded {
+				t.Fatalf("decoding expected %q got %q", i.decoded, decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeFixed_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []struct {
+		number string
+		width  int
+	}{{"", 4}, {"1", 2}, {"-1.5", 3}, {"1.2.3", 8}, {"0", 0}} {
+		if _, ok := c.EncodeFixed(input.number, input.width); ok {
+			t.Fatalf("encoding should have failed for %q with width %d", input.number, input.width)
+		}
+	}
+	for _, input := range []string{"", "50010", "8100", "7", "7z", "3yy~"} {
+		if _, ok := c.DecodeFixed(input); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestFixedWidth(t *testing.T) {
+	testCases := []struct {
+		intDigits  int
+		fracDigits int
+		inputs     []string
+	}{
+		{intDigits: 3, fracDigits: 2, inputs: []string{"-999.99", "-100.01", "-0.01", "0.99"}},
+		{intDigits: 0, fracDigits: 5, inputs: []string{"-0.99999", "-0.00001", "0.10001"}},
+		{intDigits: 40, fracDigits: 0, inputs: []string{"-" + strings.Repeat("9", 40), "-1" + strings.Repeat("0", 38) + "1"}},
+		{intDigits: 1, fracDigits: 70, inputs: []string{"-9." + strings.Repeat("9", 70), "-0." + strings.Repeat("0", 69) + "1"}},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		width := FixedWidth(i.intDigits, i.fracDigits)
+		for _, input := range i.inputs {
+			if _, ok := c.EncodeFixed(input, width); !ok {
+				t.Fatalf("%q does not fit into width %d", input, width)
+			}
+		}
+	}
+}
+
+func TestEncodeFixed_Order(t *testing.T) {
+	c := new(Codec)
+	width := FixedWidth(5, 4)
+	randomNumber := func() string {
+		number := "0" + randomString([]byte("0123456789"), 4) + "." + randomString([]byte("0123456789"), 4)
+		if rand.Intn(2) == 0 {
+			number = "-" + number
+		}
+		return strings.TrimSuffix(number, ".")
+	}
+
+	rand.Seed(42)
+	for i := 0; i < 20000; i++ {
+		a, b := randomNumber(), randomNumber()
+		tokenA, _ := c.EncodeToken(a)
+		tokenB, _ := c.EncodeToken(b)
+		fixedA, ok := c.EncodeFixed(a, width)
+		if !ok || len(fixedA) != width {
+			t.Fatalf("encoding failed for %q: %q", a, fixedA)
+		}
+		fixedB, _ := c.EncodeFixed(b, width)
+		if compareStrings(tokenA, tokenB) != compareStrings(fixedA, fixedB) {
+			t.Fatalf("%s and %s sort differently (%q and %q)", a, b, fixedA, fixedB)
+		}
+		expected, _ := c.DecodeToken(tokenA)
+		if decoded, _ := c.DecodeFixed(fixedA); decoded != expected {
+			t.Fatalf("decoding expected %q got %q", expected, decoded)
+		}
+	}
+}
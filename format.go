@@ -0,0 +1,171 @@
+package conust
+
+// Format identifies one of the text formats a number can be stored in.
+type Format int
+
+const (
+	// FormatToken is the format of EncodeToken.
+	FormatToken Format = iota
+	// FormatDense is the format of EncodeDense.
+	FormatDense
+	// FormatLog is the format of EncodeTokenLog.
+	FormatLog
+	// FormatCollation is the format of EncodeTokenAlphabet with AlphabetCollation.
+	FormatCollation
+	// FormatURL is the format of EncodeTokenAlphabet with AlphabetURL.
+	FormatURL
+	// FormatFilename is the format of EncodeTokenAlphabet with AlphabetFilename.
+	FormatFilename
+)
+
+// formatInfo holds the name and the marker of every format. The markers are lower case letters, which
+// no format starts its tokens with, and which are safe in every alphabet.
+var formatInfo = [...]struct {
+	name   string
+	marker byte
+}{
+	FormatToken:     {"token", 't'},
+	FormatDense:     {"dense", 'd'},
+	FormatLog:       {"log", 'l'},
+	FormatCollation: {"collation", 'c'},
+	FormatURL:       {"url", 'u'},
+	FormatFilename:  {"filename", 'f'},
+}
+
+// String returns the name of the format, as accepted by ParseFormat.
+func (f Format) String() string {
+	if !f.valid() {
+		return "unknown"
+	}
+	return formatInfo[f].name
+}
+
+func (f Format) valid() bool {
+	return f >= 0 && int(f) < len(formatInfo)
+}
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (format Format, ok bool) {
+	for i, info := range formatInfo {
+		if info.name == name {
+			return Format(i), true
+		}
+	}
+	return 0, false
+}
+
+// AddFormatMarker prefixes a token with the marker of its format, a single lower case letter, so that
+// DetectFormat can tell the format for sure. Tokens of the same format share the marker, so they keep
+// their order, and tokens of different formats sort in separate groups.
+func AddFormatMarker(token string, format Format) string {
+	if token == "" || !format.valid() {
+		return token
+	}
+	return string(formatInfo[format].marker) + token
+}
+
+// DetectFormat tells the format of a token. A token with a format marker is checked against the format
+// of the marker. Without a marker, the token is tried in every format, and detection fails unless exactly
+// one of them decodes it. Many tokens are valid in more than one format, like "711", which is 1 both as
+// FormatToken and FormatURL, and every token of FormatCollation is one of FormatFilename as well, so
+// only the markers make detection reliable.
+func (c *Codec) DetectFormat(token string) (format Format, ok bool) {
+	if token == "" {
+		return 0, false
+	}
+	for i, info := range formatInfo {
+		if token[0] == info.marker {
+			_, ok = c.decodeFormat(token[1:], Format(i))
+			return Format(i), ok
+		}
+	}
+	for i := range formatInfo {
+		if _, decoded := c.decodeFormat(token, Format(i)); decoded {
+			if ok {
+				return 0, false
+			}
+			format, ok = Format(i), true
+		}
+	}
+	return format, ok
+}
+
+// Migrate re-encodes a token of one format in another one. The token may have the format marker of its
+// format, and the output has a marker if the input had one. Migrate verifies that the new token decodes to
+// the same number as the original one, and fails if it does not, or if the number has no representation
+// in the new format, like a number of base 36 in FormatDense.
+func (c *Codec) Migrate(token string, from Format, to Format) (out string, ok bool) {
+	if !from.valid() || !to.valid() {
+		return "", false
+	}
+	if token == "" {
+		return "", true
+	}
+
+	marked := token[0] == formatInfo[from].marker
+	if marked {
+		token = token[1:]
+	}
+	number, ok := c.decodeFormat(token, from)
+	if !ok {
+		return "", false
+	}
+	out, ok = c.encodeFormat(number, to)
+	if !ok {
+		return "", false
+	}
+	if check, ok := c.decodeFormat(out, to); !ok || check != number {
+		return "", false
+	}
+	if marked {
+		out = AddFormatMarker(out, to)
+	}
+	return out, true
+}
+
+func (c *Codec) encodeFormat(number string, format Format) (out string, ok bool) {
+	switch format {
+	case FormatToken:
+		return c.EncodeToken(number)
+	case FormatDense:
+		return c.EncodeDense(number)
+	case FormatLog:
+		return c.EncodeTokenLog(number)
+	case FormatCollation:
+		return c.EncodeTokenAlphabet(number, AlphabetCollation)
+	case FormatURL:
+		return c.EncodeTokenAlphabet(number, AlphabetURL)
+	case FormatFilename:
+		return c.EncodeTokenAlphabet(number, AlphabetFilename)
+	}
+	return "", false
+}
+
+func (c *Codec) decodeFormat(token string, format Format) (number string, ok bool) {
+	if token == "" {
+		return "", false
+	}
+	switch format {
+	case FormatToken:
+		number, ok = c.DecodeToken(token)
+	case FormatDense:
+		number, ok = c.DecodeDense(token)
+	case FormatLog:
+		number, ok = c.DecodeTokenLog(token)
+	case FormatCollation:
+		number, ok = c.DecodeTokenAlphabet(token, AlphabetCollation)
+	case FormatURL:
+		number, ok = c.DecodeTokenAlphabet(token, AlphabetURL)
+	case FormatFilename:
+		number, ok = c.DecodeTokenAlphabet(token, AlphabetFilename)
+	}
+	if !ok {
+		return "", false
+	}
+	// The decoders accept some tokens that the encoders never produce, a token is only taken as one of
+	// the format if encoding its number gives it back.
+	if encoded, _ := c.encodeFormat(number, format); encoded != token {
+		return "", false
+	}
+	return number, true
+}
@@ -0,0 +1,114 @@
+package conust
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for format := FormatToken; format <= FormatFilename; format++ {
+		parsed, ok := ParseFormat(format.String())
+		if !ok || parsed != format {
+			t.Fatalf("format %d parsed as %d from %q", format, parsed, format.String())
+		}
+	}
+	if _, ok := ParseFormat("unknown"); ok {
+		t.Fatal("parsing should have failed for \"unknown\"")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	testCases := []struct {
+		token  string
+		format Format
+		ok     bool
+	}{
+		{token: "", ok: false},
+		{token: "7212", ok: false},
+		{token: "3yy~", format: FormatToken, ok: true},
+		{token: "E#-", format: FormatDense, ok: true},
+		{token: "J1212", format: FormatLog, ok: true},
+		{token: "8323", ok: false},
+		{token: "711", ok: false},
+		{token: "4z1z1z3", ok: false},
+		{token: "7z26", ok: false},
+		{token: "3YY_", format: FormatURL, ok: true},
+		{token: "c8323", format: FormatCollation, ok: true},
+		{token: "f8323", format: FormatFilename, ok: true},
+		{token: "u7212", format: FormatURL, ok: true},
+		{token: "t7212", format: FormatToken, ok: true},
+		{token: "t721", format: FormatToken, ok: true},
+		{token: "d7212", format: FormatDense, ok: false},
+		{token: "7210", ok: false},
+		{token: "x7212", ok: false},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.token, func(t *testing.T) {
+			format, ok := c.DetectFormat(i.token)
+			if ok != i.ok || (ok && format != i.format) {
+				t.Fatalf("expected %v, %v got %v, %v", i.format, i.ok, format, ok)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	testCases := []struct {
+		token    string
+		from     Format
+		to       Format
+		migrated string
+		ok       bool
+	}{
+		{token: "", from: FormatToken, to: FormatDense, migrated: "", ok: true},
+		{token: "7212", from: FormatToken, to: FormatDense, migrated: "E#-", ok: true},
+		{token: "t7212", from: FormatToken, to: FormatCollation, migrated: "c8323", ok: true},
+		{token: "E#-", from: FormatDense, to: FormatLog, migrated: "J1212", ok: true},
+		{token: "72zz", from: FormatToken, to: FormatDense, ok: false},
+		{token: "7212", from: FormatDense, to: FormatToken, ok: false},
+		{token: "7212", from: FormatToken, to: Format(-1), ok: false},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.token, func(t *testing.T) {
+			migrated, ok := c.Migrate(i.token, i.from, i.to)
+			if ok != i.ok || migrated != i.migrated {
+				t.Fatalf("expected %q, %v got %q, %v", i.migrated, i.ok, migrated, ok)
+			}
+		})
+	}
+}
+
+func TestMigrate_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 5000; i++ {
+		a := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(4), 64)
+		b := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(4), 64)
+		tokenA, _ := c.EncodeToken(a)
+		tokenB, _ := c.EncodeToken(b)
+		expected := compareStrings(tokenA, tokenB)
+
+		for to := FormatToken; to <= FormatFilename; to++ {
+			migratedA, okA := c.Migrate(AddFormatMarker(tokenA, FormatToken), FormatToken, to)
+			migratedB, okB := c.Migrate(AddFormatMarker(tokenB, FormatToken), FormatToken, to)
+			if !okA || !okB {
+				t.Fatalf("migrating %q or %q to %s failed", tokenA, tokenB, to)
+			}
+			if got := compareStrings(migratedA, migratedB); got != expected {
+				t.Fatalf("%s and %s sort differently in %s (%q and %q)", a, b, to, migratedA, migratedB)
+			}
+			if format, ok := c.DetectFormat(migratedA); !ok || format != to {
+				t.Fatalf("format of %q detected as %s", migratedA, format)
+			}
+			back, ok := c.Migrate(migratedA, to, FormatToken)
+			if !ok || back != AddFormatMarker(tokenA, FormatToken) {
+				t.Fatalf("migrating %q back expected %q got %q", migratedA, tokenA, back)
+			}
+		}
+	}
+}
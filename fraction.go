@@ -0,0 +1,147 @@
+package conust
+
+import (
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultFractionPrecision is the number of significant digits kept of non terminating fractions
+// when FractionRecognizer.Precision is not set.
+const defaultFractionPrecision = 20
+
+const fractionSlash = '/'
+const unicodeFractionSlash = '⁄'
+
+var vulgarFractions = map[rune][2]int64{
+	'½': {1, 2}, '⅓': {1, 3}, '⅔': {2, 3}, '¼': {1, 4}, '¾': {3, 4},
+	'⅕': {1, 5}, '⅖': {2, 5}, '⅗': {3, 5}, '⅘': {4, 5}, '⅙': {1, 6},
+	'⅚': {5, 6}, '⅐': {1, 7}, '⅛': {1, 8}, '⅜': {3, 8}, '⅝': {5, 8},
+	'⅞': {7, 8}, '⅑': {1, 9}, '⅒': {1, 10},
+}
+
+// FractionRecognizer recognizes fractions like "3/16", mixed numbers like "2 1/2", and the Unicode
+// vulgar fractions like "½" or "2¾" in mixed text, and encodes them by their value. So "bolt 1/4"
+// sorts before "bolt 3/8".
+//
+// Values with a non terminating decimal expansion, like 1/3, are truncated to Precision significant
+// digits, which keeps their order but makes values closer than that compare equal.
+// Something that looks like a date ("1/5/2024") is not a fraction.
+type FractionRecognizer struct {
+	// Precision is the number of significant digits kept of non terminating values, 20 if not set.
+	Precision int
+}
+
+// Recognize implements the Recognizer interface.
+func (r FractionRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	if pos > 0 && (isWordByte(input[pos-1]) || input[pos-1] == decimalPoint || input[pos-1] == fractionSlash) {
+		return 0, "", false
+	}
+
+	value := new(big.Rat)
+	end := pos
+	if isDecimalDigit(input[pos]) {
+		whole, wholeEnd := parseDigits(input, pos)
+		if fraction, fractionEnd, found := parseFraction(input, wholeEnd); found {
+			value.Add(whole, fraction)
+			end = fractionEnd
+		} else if wholeEnd < len(input) && input[wholeEnd] == ' ' && wholeEnd+1 < len(input) && isDecimalDigit(input[wholeEnd+1]) {
+			numerator, numeratorEnd := parseDigits(input, wholeEnd+1)
+			if fraction, fractionEnd, found := parseSlashFraction(input, numerator, numeratorEnd); found {
+				value.Add(whole, fraction)
+				end = fractionEnd
+			}
+		}
+		if end == pos {
+			if fraction, fractionEnd, found := parseSlashFraction(input, whole, wholeEnd); found {
+				value = fraction
+				end = fractionEnd
+			}
+		}
+	} else if fraction, fractionEnd, found := parseFraction(input, pos); found {
+		value = fraction
+		end = fractionEnd
+	}
+
+	if end == pos || (end < len(input) && (isWordByte(input[end]) || input[end] == fractionSlash)) {
+		return 0, "", false
+	}
+
+	precision := r.Precision
+	if precision <= 0 {
+		precision = defaultFractionPrecision
+	}
+	token, ok = c.EncodeToken(truncateRat(value, precision))
+	return end - pos, token, ok
+}
+
+// parseFraction reads a vulgar fraction character starting at pos.
+func parseFraction(input string, pos int) (value *big.Rat, end int, ok bool) {
+	if pos >= len(input) {
+		return nil, 0, false
+	}
+	ch, size := utf8.DecodeRuneInString(input[pos:])
+	fraction, ok := vulgarFractions[ch]
+	if !ok {
+		return nil, 0, false
+	}
+	return big.NewRat(fraction[0], fraction[1]), pos + size, true
+}
+
+// parseSlashFraction reads the slash and the denominator that follow an already parsed numerator.
+func parseSlashFraction(input string, numerator *big.Rat, pos int) (value *big.Rat, end int, ok bool) {
+	if pos >= len(input) {
+		return nil, 0, false
+	}
+	ch, size := utf8.DecodeRuneInString(input[pos:])
+	if ch != fractionSlash && ch != unicodeFractionSlash {
+		return nil, 0, false
+	}
+	if pos+size >= len(input) || !isDecimalDigit(input[pos+size]) {
+		return nil, 0, false
+	}
+	denominator, end := parseDigits(input, pos+size)
+	if denominator.Sign() == 0 {
+		return nil, 0, false
+	}
+	return new(big.Rat).Quo(numerator, denominator), end, true
+}
+
+func parseDigits(input string, pos int) (value *big.Rat, end int) {
+	end = pos
+	for end < len(input) && isDecimalDigit(input[end]) {
+		end++
+	}
+	value, _ = new(big.Rat).SetString(input[pos:end])
+	return value, end
+}
+
+// truncateRat writes a non negative rational number as a decimal number, truncating non terminating
+// expansions to the given number of significant digits.
+func truncateRat(value *big.Rat, significantDigits int) string {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	var b strings.Builder
+	integer := quotient.String()
+	b.WriteString(integer)
+
+	significant := 0
+	if quotient.Sign() != 0 {
+		significant = len(integer)
+	}
+	if remainder.Sign() == 0 || significant >= significantDigits {
+		return b.String()
+	}
+
+	b.WriteByte(decimalPoint)
+	ten := big.NewInt(10)
+	digit := new(big.Int)
+	for remainder.Sign() != 0 && significant < significantDigits {
+		remainder.Mul(remainder, ten)
+		digit.QuoRem(remainder, value.Denom(), remainder)
+		b.WriteByte(intToDigit(int(digit.Int64())))
+		if significant > 0 || digit.Sign() != 0 {
+			significant++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package conust
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFractionRecognizer(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		precision int
+		output    string
+	}{
+		{name: "half", input: "1/2", output: "6z5"},
+		{name: "sixteenths", input: "bolt 3/16\"", output: "bolt 6z1875 \""},
+		{name: "mixed number", input: "2 1/2 in", output: "7125 in"},
+		{name: "vulgar", input: "¾ cup", output: "6z75 cup"},
+		{name: "mixed vulgar", input: "2½", output: "7125"},
+		{name: "fraction slash", input: "5⁄8", output: "6z625"},
+		{name: "improper", input: "10/4", output: "7125"},
+		{name: "non terminating", input: "1/3", precision: 5, output: "6z33333"},
+		{name: "non terminating with integer part", input: "100/3", precision: 3, output: "72333"},
+		{name: "date", input: "1/5/2024", output: "711 / 715 / 742024"},
+		{name: "zero denominator", input: "1/0", output: "711 / 5"},
+		{name: "inside a word", input: "x1/2", output: "x 711 / 712"},
+		{name: "plain numbers", input: "2 3", output: "712 713"},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			c := &Codec{Recognizers: []Recognizer{FractionRecognizer{Precision: i.precision}}}
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.output {
+				t.Fatalf("output expected %q got %q", i.output, encoded)
+			}
+		})
+	}
+}
+
+func TestFractionRecognizer_Order(t *testing.T) {
+	ordered := []string{
+		"bolt 1/16\"",
+		"bolt 1/8\"",
+		"bolt 3/16\"",
+		"bolt ¼\"",
+		"bolt 1/3\"",
+		"bolt 3/8\"",
+		"bolt 1/2\"",
+		"bolt 1\"",
+		"bolt 1 1/4\"",
+		"bolt 1⅓\"",
+		"bolt 2\"",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{FractionRecognizer{}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
+
+func TestTruncateRat(t *testing.T) {
+	testCases := []struct {
+		value    *big.Rat
+		digits   int
+		expected string
+	}{
+		{big.NewRat(0, 1), 5, "0"},
+		{big.NewRat(1, 8), 5, "0.125"},
+		{big.NewRat(2, 3), 4, "0.6666"},
+		{big.NewRat(1, 300), 2, "0.0033"},
+		{big.NewRat(12345, 1), 2, "12345"},
+		{big.NewRat(12346, 10), 2, "1234"},
+	}
+
+	for _, i := range testCases {
+		if out := truncateRat(i.value, i.digits); out != i.expected {
+			t.Fatalf("%v expected %q got %q", i.value, i.expected, out)
+		}
+	}
+}
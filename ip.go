@@ -0,0 +1,195 @@
+package conust
+
+import (
+	"encoding/hex"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const addrFamilyIPv4 byte = '4'
+const addrFamilyIPv6 byte = '6'
+
+// addrTokenPrefix starts the tokens of IPRecognizer. Like the prefix of DateRecognizer, it sorts after the
+// sign bytes of all numbers, so addresses form their own class in mixed text and never equal a number.
+const addrTokenPrefix byte = '8'
+
+// EncodeAddr turns an IP address into a string for which the string order equals the address order.
+// IPv4 addresses sort before IPv6 addresses, and IPv4-mapped IPv6 addresses are treated as the IPv4
+// address they contain. Zones are ignored.
+//
+// The output is a family byte followed by the Conust token of the hexadecimal value of the address,
+// so DecodeToken can recover that value from it.
+func (c *Codec) EncodeAddr(addr netip.Addr) (out string, ok bool) {
+	if !addr.IsValid() {
+		return "", false
+	}
+
+	addr = addr.Unmap()
+	family := addrFamilyIPv6
+	if addr.Is4() {
+		family = addrFamilyIPv4
+	}
+	raw := addr.AsSlice()
+
+	token, ok := c.EncodeToken(hex.EncodeToString(raw))
+	if !ok {
+		return "", false
+	}
+	return string(family) + token, true
+}
+
+// EncodeIP is EncodeAddr for net.IP values.
+func (c *Codec) EncodeIP(ip net.IP) (out string, ok bool) {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return "", false
+	}
+	return c.EncodeAddr(addr)
+}
+
+// DecodeAddr turns a string generated by EncodeAddr back into the address.
+func (c *Codec) DecodeAddr(input string) (addr netip.Addr, ok bool) {
+	if len(input) < 2 {
+		return netip.Addr{}, false
+	}
+
+	size := 16
+	switch input[0] {
+	case addrFamilyIPv4:
+		size = 4
+	case addrFamilyIPv6:
+	default:
+		return netip.Addr{}, false
+	}
+
+	value, ok := c.DecodeToken(input[1:])
+	if !ok || len(value) > 2*size || strings.IndexByte(value, decimalPoint) >= 0 || value[0] == minusByte {
+		return netip.Addr{}, false
+	}
+
+	raw, err := hex.DecodeString(strings.Repeat(zeroInput, 2*size-len(value)) + value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return netip.AddrFromSlice(raw)
+}
+
+// EncodePrefix turns a CIDR prefix into a string that sorts by the network address first and by the
+// prefix length second, so a network sorts right before its subnets. The address is masked, and
+// IPv4-mapped prefixes are treated as IPv4 prefixes.
+func (c *Codec) EncodePrefix(prefix netip.Prefix) (out string, ok bool) {
+	prefix, ok = normalizePrefix(prefix)
+	if !ok {
+		return "", false
+	}
+
+	addr, ok := c.EncodeAddr(prefix.Addr())
+	if !ok {
+		return "", false
+	}
+	bits, _ := c.EncodeToken(strconv.Itoa(prefix.Bits()))
+	return addr + string(inTextSeparator) + bits, true
+}
+
+// EncodePrefixRange returns the EncodeAddr version of the first and the last address of the prefix.
+// An address belongs to the prefix exactly when its encoded version is between the two, inclusive,
+// which makes it possible to look up a network with a range query.
+func (c *Codec) EncodePrefixRange(prefix netip.Prefix) (from string, to string, ok bool) {
+	prefix, ok = normalizePrefix(prefix)
+	if !ok {
+		return "", "", false
+	}
+
+	first := prefix.Addr()
+	raw := first.AsSlice()
+	for i := prefix.Bits(); i < len(raw)*8; i++ {
+		raw[i/8] |= 0x80 >> uint(i%8)
+	}
+	last, _ := netip.AddrFromSlice(raw)
+
+	from, _ = c.EncodeAddr(first)
+	to, _ = c.EncodeAddr(last)
+	return from, to, true
+}
+
+func normalizePrefix(prefix netip.Prefix) (netip.Prefix, bool) {
+	if !prefix.IsValid() {
+		return netip.Prefix{}, false
+	}
+	addr := prefix.Addr()
+	if addr.Is4In6() {
+		if prefix.Bits() < 96 {
+			return prefix.Masked(), true
+		}
+		return netip.PrefixFrom(addr.Unmap(), prefix.Bits()-96).Masked(), true
+	}
+	return prefix.Masked(), true
+}
+
+// IPRecognizer recognizes IPv4 and IPv6 addresses, and CIDR prefixes written with them, in mixed text,
+// and encodes them with EncodeAddr and EncodePrefix. So "host 10.0.0.9" sorts before "host 10.0.0.10".
+//
+// The token of an address is prefixed with "8", so addresses sort after all numbers and before the dates
+// of DateRecognizer, IPv4 before IPv6.
+type IPRecognizer struct{}
+
+// Recognize implements the Recognizer interface.
+func (IPRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	if pos > 0 && (isWordByte(input[pos-1]) || input[pos-1] == '.' || input[pos-1] == ':') {
+		return 0, "", false
+	}
+
+	end := pos
+	colons := 0
+	for end < len(input) && (isHexDigit(input[end]) || input[end] == '.' || input[end] == ':') {
+		if input[end] == ':' {
+			colons++
+		}
+		end++
+	}
+	// a trailing dot or colon is punctuation of the text
+	for end > pos && (input[end-1] == '.' || input[end-1] == ':') {
+		end--
+	}
+	if end == pos || (colons == 0 && !isDecimalDigit(input[pos])) {
+		return 0, "", false
+	}
+
+	addr, err := netip.ParseAddr(input[pos:end])
+	if err != nil {
+		// an IPv4 address followed by a port
+		colon := strings.IndexByte(input[pos:end], ':')
+		if colon < 0 {
+			return 0, "", false
+		}
+		end = pos + colon
+		if addr, err = netip.ParseAddr(input[pos:end]); err != nil || !addr.Is4() {
+			return 0, "", false
+		}
+	}
+
+	if end < len(input) && input[end] == '/' {
+		bitsEnd := end + 1
+		for bitsEnd < len(input) && isDecimalDigit(input[bitsEnd]) {
+			bitsEnd++
+		}
+		if bitsEnd > end+1 && (bitsEnd == len(input) || !isWordByte(input[bitsEnd])) {
+			if prefix, err := netip.ParsePrefix(input[pos:bitsEnd]); err == nil {
+				token, ok := c.EncodePrefix(prefix)
+				return bitsEnd - pos, string(addrTokenPrefix) + token, ok
+			}
+		}
+	}
+
+	if end < len(input) && isWordByte(input[end]) {
+		return 0, "", false
+	}
+	token, ok = c.EncodeAddr(addr)
+	return end - pos, string(addrTokenPrefix) + token, ok
+}
+
+func isHexDigit(ch byte) bool {
+	return isDecimalDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
@@ -0,0 +1,220 @@
+package conust
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+)
+
+func TestEncodeAddr(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		encoded string
+	}{
+		{name: "ipv4 zero", input: "0.0.0.0", encoded: "45"},
+		{name: "ipv4", input: "192.168.0.1", encoded: "478c0a80001"},
+		{name: "ipv4 mapped", input: "::ffff:192.168.0.1", encoded: "478c0a80001"},
+		{name: "ipv6 loopback", input: "::1", encoded: "6711"},
+		{name: "ipv6", input: "2001:db8::1", encoded: "67w20010db8000000000000000000000001"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			addr := netip.MustParseAddr(i.input)
+			encoded, ok := c.EncodeAddr(addr)
+			if !ok {
+				t.Fatalf("encoding failed for %v", addr)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeAddr(encoded)
+			if !ok {
+				t.Fatalf("decoding failed for %q", encoded)
+			}
+			if decoded != addr.Unmap() {
+				t.Fatalf("decoding expected %v got %v", addr.Unmap(), decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeAddr_Order(t *testing.T) {
+	ordered := []string{
+		"0.0.0.0",
+		"9.255.255.255",
+		"10.0.0.9",
+		"10.0.0.10",
+		"10.0.1.0",
+		"192.168.0.1",
+		"255.255.255.255",
+		"::",
+		"::1",
+		"2001:db8::1",
+		"2001:db8::10",
+		"fe80::1",
+		"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+	}
+
+	c := new(Codec)
+	prev, _ := c.EncodeAddr(netip.MustParseAddr(ordered[0]))
+	for i := 1; i < len(ordered); i++ {
+		encoded, ok := c.EncodeAddr(netip.MustParseAddr(ordered[i]))
+		if !ok {
+			t.Fatalf("encoding failed for %q", ordered[i])
+		}
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
+
+func TestEncodeIP(t *testing.T) {
+	c := new(Codec)
+	fromIP, ok := c.EncodeIP(net.ParseIP("10.0.0.1"))
+	if !ok {
+		t.Fatal("encoding failed")
+	}
+	fromAddr, _ := c.EncodeAddr(netip.MustParseAddr("10.0.0.1"))
+	if fromIP != fromAddr {
+		t.Fatalf("net.IP encoded as %q, netip.Addr as %q", fromIP, fromAddr)
+	}
+
+	if _, ok := c.EncodeIP(net.IP{1, 2, 3}); ok {
+		t.Fatal("encoding an invalid net.IP should fail")
+	}
+	if _, ok := c.EncodeAddr(netip.Addr{}); ok {
+		t.Fatal("encoding the zero netip.Addr should fail")
+	}
+}
+
+func TestDecodeAddr_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []string{"", "4", "5711", "4x", "4799999999999", "4611", "43yy~"} {
+		if _, ok := c.DecodeAddr(input); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestEncodePrefix(t *testing.T) {
+	ordered := []string{
+		"10.0.0.0/8",
+		"10.0.0.0/16",
+		"10.0.0.0/24",
+		"10.0.1.0/24",
+		"10.1.0.0/16",
+		"192.168.0.0/16",
+		"2001:db8::/32",
+	}
+
+	c := new(Codec)
+	prev, _ := c.EncodePrefix(netip.MustParsePrefix(ordered[0]))
+	for i := 1; i < len(ordered); i++ {
+		encoded, ok := c.EncodePrefix(netip.MustParsePrefix(ordered[i]))
+		if !ok {
+			t.Fatalf("encoding failed for %q", ordered[i])
+		}
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+
+	masked, _ := c.EncodePrefix(netip.MustParsePrefix("10.1.2.3/8"))
+	mapped, _ := c.EncodePrefix(netip.MustParsePrefix("::ffff:10.0.0.0/104"))
+	expected, _ := c.EncodePrefix(netip.MustParsePrefix("10.0.0.0/8"))
+	if masked != expected || mapped != expected {
+		t.Fatalf("expected %q got %q and %q", expected, masked, mapped)
+	}
+}
+
+func TestEncodePrefixRange(t *testing.T) {
+	c := new(Codec)
+	from, to, ok := c.EncodePrefixRange(netip.MustParsePrefix("10.0.0.0/24"))
+	if !ok {
+		t.Fatal("encoding failed")
+	}
+
+	testCases := []struct {
+		addr   string
+		inside bool
+	}{
+		{"9.255.255.255", false},
+		{"10.0.0.0", true},
+		{"10.0.0.9", true},
+		{"10.0.0.255", true},
+		{"10.0.1.0", false},
+		{"::ffff:10.0.0.7", true},
+		{"::1", false},
+	}
+	for _, i := range testCases {
+		encoded, _ := c.EncodeAddr(netip.MustParseAddr(i.addr))
+		if inside := from <= encoded && encoded <= to; inside != i.inside {
+			t.Fatalf("%s inside expected %v got %v", i.addr, i.inside, inside)
+		}
+	}
+}
+
+func TestIPRecognizer(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{name: "ipv4", input: "host 10.0.0.9", output: "host 8477a000009"},
+		{name: "ipv4 followed by text", input: "10.0.0.9:80", output: "8477a000009 : 728"},
+		{name: "sentence end", input: "at 10.0.0.9.", output: "at 8477a000009 ."},
+		{name: "prefix", input: "net 10.0.0.0/8 x", output: "net 8477a 718 x"},
+		{name: "ipv6", input: "via fe80::1 now", output: "via 867wfe800000000000000000000000000001 now"},
+		{name: "too many groups", input: "1.2.3.4.5", output: "711 . 712 . 713 . 714 . 715"},
+		{name: "inside a word", input: "a1.2.3.4", output: "a 711 . 712 . 713 . 714"},
+		{name: "not an address", input: "std::vector 300", output: "std::vector 733"},
+	}
+
+	c := &Codec{Recognizers: []Recognizer{IPRecognizer{}}}
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.output {
+				t.Fatalf("output expected %q got %q", i.output, encoded)
+			}
+		})
+	}
+
+	lower, _ := c.EncodeMixedText("host 10.0.0.9")
+	higher, _ := c.EncodeMixedText("host 10.0.0.10")
+	if lower >= higher {
+		t.Fatal("host 10.0.0.9 does not sort before host 10.0.0.10")
+	}
+}
+
+func TestIPRecognizer_PlainNumbers(t *testing.T) {
+	ordered := []string{
+		"0",
+		"1",
+		"4294967295",
+		"340282366920938463463374607431768211455",
+		"0.0.0.0",
+		"10.0.0.1",
+		"::1",
+		"fe80::1",
+		"Jan 1, 1970",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{IPRecognizer{}, DateRecognizer{}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
@@ -0,0 +1,276 @@
+package conust
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const keyTagName = "conust"
+
+type keyFieldKind int
+
+const (
+	keyFieldInt keyFieldKind = iota
+	keyFieldUint
+	keyFieldFloat
+	keyFieldBool
+	keyFieldString
+	keyFieldTime
+	keyFieldStruct
+)
+
+type keyField struct {
+	index   int
+	order   int
+	kind    keyFieldKind
+	pointer bool
+	desc    bool
+	natural bool
+	nested  *keyPlan
+}
+
+type keyPlan struct {
+	fields []keyField
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// keyPlans caches the plan of every struct type KeyOf has seen, or the error describing why the type
+// cannot be turned into a key.
+var keyPlans sync.Map
+
+// KeyOf builds a sort key from the fields of a struct (or a pointer to one) that have a conust tag. The
+// tag holds the position of the field in the key, optionally followed by options, like in
+//
+//	type Track struct {
+//		Album  string    `conust:"1,natural"`
+//		Number int       `conust:"2"`
+//		Rating float64   `conust:"3,desc"`
+//		Added  time.Time `conust:"4,desc"`
+//	}
+//
+// The fields are added to a tuple key in the order of their positions, so the keys sort by the first
+// field, then by the second one, and so on. The "desc" option reverses the order of a field, and the
+// "natural" option encodes a string field with EncodeMixedText, so the numbers in it sort by value.
+// Integers, floating point numbers, booleans, strings and times are supported, as are pointers to them,
+// where nil sorts first like a null, and nested structs with tagged fields, whose fields are added in
+// place. The key can be decoded with DecodeKey.
+//
+// The way a type is turned into a key is worked out once and cached, so repeated calls are cheap.
+func KeyOf(v interface{}) (string, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return "", fmt.Errorf("conust: KeyOf called with a nil %s", value.Type())
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return "", fmt.Errorf("conust: KeyOf needs a struct, got %v", value.Kind())
+	}
+
+	plan, err := keyPlanOf(value.Type())
+	if err != nil {
+		return "", err
+	}
+
+	k := Key()
+	plan.append(k, value, false)
+	key, ok := k.Bytes()
+	if !ok {
+		return "", fmt.Errorf("conust: cannot encode the value of a field of %s", value.Type())
+	}
+	return string(key), nil
+}
+
+func keyPlanOf(t reflect.Type) (*keyPlan, error) {
+	if cached, found := keyPlans.Load(t); found {
+		if err, isError := cached.(error); isError {
+			return nil, err
+		}
+		return cached.(*keyPlan), nil
+	}
+
+	plan, err := newKeyPlan(t, nil)
+	if err != nil {
+		keyPlans.Store(t, err)
+		return nil, err
+	}
+	keyPlans.Store(t, plan)
+	return plan, nil
+}
+
+// newKeyPlan works out how to turn a struct type into a key. Parents holds the struct types the type is
+// nested in, to reject recursive types.
+func newKeyPlan(t reflect.Type, parents []reflect.Type) (*keyPlan, error) {
+	for _, parent := range parents {
+		if parent == t {
+			return nil, fmt.Errorf("conust: %s contains itself", t)
+		}
+	}
+	parents = append(parents, t)
+
+	plan := new(keyPlan)
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		tag, found := structField.Tag.Lookup(keyTagName)
+		if !found || tag == "-" {
+			continue
+		}
+		if structField.PkgPath != "" {
+			return nil, fmt.Errorf("conust: tagged field %s.%s is not exported", t, structField.Name)
+		}
+
+		field, err := parseKeyTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("conust: field %s.%s: %v", t, structField.Name, err)
+		}
+		field.index = i
+
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Ptr {
+			field.pointer = true
+			fieldType = fieldType.Elem()
+		}
+		field.kind, err = keyFieldKindOf(fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("conust: field %s.%s: %v", t, structField.Name, err)
+		}
+		if field.natural && field.kind != keyFieldString {
+			return nil, fmt.Errorf("conust: field %s.%s: the natural option needs a string", t, structField.Name)
+		}
+		if field.kind == keyFieldStruct {
+			if field.nested, err = newKeyPlan(fieldType, parents); err != nil {
+				return nil, err
+			}
+		}
+		plan.fields = append(plan.fields, field)
+	}
+
+	if len(plan.fields) == 0 {
+		return nil, fmt.Errorf("conust: %s has no fields with a %s tag", t, keyTagName)
+	}
+	sort.Slice(plan.fields, func(i int, j int) bool {
+		return plan.fields[i].order < plan.fields[j].order
+	})
+	for i := 1; i < len(plan.fields); i++ {
+		if plan.fields[i-1].order == plan.fields[i].order {
+			return nil, fmt.Errorf("conust: %s has more than one field at position %d", t, plan.fields[i].order)
+		}
+	}
+	return plan, nil
+}
+
+func parseKeyTag(tag string) (field keyField, err error) {
+	parts := strings.Split(tag, ",")
+	field.order, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return field, fmt.Errorf("invalid position %q", parts[0])
+	}
+	for _, option := range parts[1:] {
+		switch option {
+		case "desc":
+			field.desc = true
+		case "natural":
+			field.natural = true
+		default:
+			return field, fmt.Errorf("unknown option %q", option)
+		}
+	}
+	return field, nil
+}
+
+func keyFieldKindOf(t reflect.Type) (keyFieldKind, error) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return keyFieldInt, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return keyFieldUint, nil
+	case reflect.Float32, reflect.Float64:
+		return keyFieldFloat, nil
+	case reflect.Bool:
+		return keyFieldBool, nil
+	case reflect.String:
+		return keyFieldString, nil
+	case reflect.Struct:
+		if t == timeType {
+			return keyFieldTime, nil
+		}
+		return keyFieldStruct, nil
+	}
+	return 0, fmt.Errorf("unsupported type %s", t)
+}
+
+// append adds the fields of the struct to the key. Desc reverses the order of all of them, when the
+// struct is nested in a descending field.
+func (p *keyPlan) append(k *KeyBuilder, value reflect.Value, desc bool) {
+	for _, field := range p.fields {
+		fieldValue := value.Field(field.index)
+		fieldDesc := field.desc != desc
+		if field.pointer {
+			if fieldValue.IsNil() {
+				if field.kind == keyFieldStruct {
+					field.nested.appendNulls(k, fieldDesc)
+				} else {
+					appendKeyNull(k, fieldDesc)
+				}
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if field.kind == keyFieldStruct {
+			field.nested.append(k, fieldValue, fieldDesc)
+			continue
+		}
+
+		if fieldDesc {
+			k.Desc()
+		}
+		switch field.kind {
+		case keyFieldInt:
+			k.Int(fieldValue.Int())
+		case keyFieldUint:
+			k.Num(strconv.FormatUint(fieldValue.Uint(), 10))
+		case keyFieldFloat:
+			k.Float(fieldValue.Float())
+		case keyFieldBool:
+			if fieldValue.Bool() {
+				k.Int(1)
+			} else {
+				k.Int(0)
+			}
+		case keyFieldString:
+			if field.natural {
+				encoded, _ := k.codec.EncodeMixedText(fieldValue.String())
+				k.Str(encoded)
+			} else {
+				k.Str(fieldValue.String())
+			}
+		case keyFieldTime:
+			k.Time(fieldValue.Interface().(time.Time))
+		}
+	}
+}
+
+// appendNulls adds a null for every field of the struct, in place of a nil pointer to it.
+func (p *keyPlan) appendNulls(k *KeyBuilder, desc bool) {
+	for _, field := range p.fields {
+		if field.kind == keyFieldStruct {
+			field.nested.appendNulls(k, field.desc != desc)
+		} else {
+			appendKeyNull(k, field.desc != desc)
+		}
+	}
+}
+
+func appendKeyNull(k *KeyBuilder, desc bool) {
+	if desc {
+		k.Desc()
+	}
+	k.Null()
+}
@@ -0,0 +1,152 @@
+package conust
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type keyOfTrack struct {
+	Album  string    `conust:"1,natural"`
+	Number int       `conust:"2"`
+	Rating *float64  `conust:"3,desc"`
+	Added  time.Time `conust:"4,desc"`
+	Title  string
+}
+
+type keyOfArtist struct {
+	Name    string      `conust:"1"`
+	Best    *keyOfTrack `conust:"3"`
+	Active  bool        `conust:"2,desc"`
+	Country uint8       `conust:"-"`
+}
+
+func TestKeyOf(t *testing.T) {
+	rating := 4.5
+	added := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
+	track := keyOfTrack{Album: "Vol 2", Number: 7, Rating: &rating, Added: added, Title: "ignored"}
+
+	key, err := KeyOf(&track)
+	if err != nil {
+		t.Fatal(err)
+	}
+	album, _ := new(Codec).EncodeMixedText("Vol 2")
+	expected, _ := Key().Str(album).Int(7).Desc().Float(4.5).Desc().Time(added).Bytes()
+	if key != string(expected) {
+		t.Fatalf("key expected %q got %q", expected, key)
+	}
+
+	artist := keyOfArtist{Name: "X", Active: true, Best: &track}
+	key, err = KeyOf(artist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ = Key().Str("X").Desc().Int(1).Str(album).Int(7).Desc().Float(4.5).Desc().Time(added).Bytes()
+	if key != string(expected) {
+		t.Fatalf("key expected %q got %q", expected, key)
+	}
+
+	key, err = KeyOf(keyOfArtist{Name: "X"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ = Key().Str("X").Desc().Int(0).Null().Null().Desc().Null().Desc().Null().Bytes()
+	if key != string(expected) {
+		t.Fatalf("key expected %q got %q", expected, key)
+	}
+}
+
+func TestKeyOf_Order(t *testing.T) {
+	high, low := 5.0, 1.0
+	day := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	ordered := []keyOfTrack{
+		{Album: "Vol 2", Number: 1, Rating: &high},
+		{Album: "Vol 2", Number: 1, Rating: &low, Added: day.Add(time.Hour)},
+		{Album: "Vol 2", Number: 1, Rating: &low, Added: day},
+		{Album: "Vol 2", Number: 1},
+		{Album: "Vol 2", Number: 10},
+		{Album: "Vol 10", Number: -3},
+		{Album: "Vol 10b", Number: -3},
+	}
+
+	keys := make([]string, len(ordered))
+	for i := range ordered {
+		key, err := KeyOf(ordered[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Fatalf("keys are not in the expected order: %q", keys)
+	}
+}
+
+func TestKeyOf_Errors(t *testing.T) {
+	type noTags struct {
+		A int
+	}
+	type badPosition struct {
+		A int `conust:"first"`
+	}
+	type badOption struct {
+		A int `conust:"1,up"`
+	}
+	type samePosition struct {
+		A int `conust:"1"`
+		B int `conust:"1"`
+	}
+	type badType struct {
+		A []int `conust:"1"`
+	}
+	type badNatural struct {
+		A int `conust:"1,natural"`
+	}
+	type unexported struct {
+		a int `conust:"1"`
+	}
+	type recursive struct {
+		A    int        `conust:"1"`
+		Next *recursive `conust:"2"`
+	}
+	var nilTrack *keyOfTrack
+
+	testCases := []struct {
+		name    string
+		value   interface{}
+		message string
+	}{
+		{name: "not a struct", value: 5, message: "needs a struct"},
+		{name: "nil", value: nilTrack, message: "nil"},
+		{name: "no tags", value: noTags{}, message: "no fields"},
+		{name: "bad position", value: badPosition{}, message: "invalid position"},
+		{name: "bad option", value: badOption{}, message: "unknown option"},
+		{name: "same position", value: samePosition{}, message: "more than one field"},
+		{name: "bad type", value: badType{}, message: "unsupported type"},
+		{name: "bad natural", value: badNatural{}, message: "natural"},
+		{name: "unexported", value: unexported{a: 1}, message: "not exported"},
+		{name: "recursive", value: recursive{}, message: "contains itself"},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			for attempt := 0; attempt < 2; attempt++ {
+				_, err := KeyOf(i.value)
+				if err == nil || !strings.Contains(err.Error(), i.message) {
+					t.Fatalf("error expected to contain %q got %v", i.message, err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkKeyOf(b *testing.B) {
+	rating := 4.5
+	track := keyOfTrack{Album: "Vol 2", Number: 7, Rating: &rating, Added: time.Now()}
+	for i := 0; i < b.N; i++ {
+		if _, err := KeyOf(&track); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package conust
+
+import (
+	"math/big"
+	"strings"
+)
+
+const digitSeparator byte = '_'
+
+// EncodeLiteral turns a Go style number literal into a Conust token by its value. Besides what
+// EncodeToken accepts for decimal numbers it understands the 0x, 0o and 0b prefixes (in either case) for
+// hexadecimal, octal and binary integers, and underscores between digits, like in "1_000_000".
+// The literal is converted to decimal before encoding, so "0x1F", "0o37", "0b11111" and "31" all become
+// the same token. Unlike in Go, a bare leading zero does not mean octal, "0755" is read as decimal.
+func (c *Codec) EncodeLiteral(input string) (out string, ok bool) {
+	if input == "" {
+		return "", false
+	}
+
+	sign := ""
+	digits := input
+	if isSignByte(digits[0]) {
+		sign = digits[:1]
+		digits = digits[1:]
+	}
+
+	base, digits := literalBase(digits)
+	if !isValidDigitSeparation(digits, base != 10) {
+		return "", false
+	}
+	digits = strings.Replace(digits, string(digitSeparator), "", -1)
+
+	if base == 10 {
+		for i := 0; i < len(digits); i++ {
+			if !isDecimalDigit(digits[i]) && digits[i] != decimalPoint {
+				return "", false
+			}
+		}
+		return c.EncodeToken(sign + digits)
+	}
+
+	for i := 0; i < len(digits); i++ {
+		if !isDigit(toLowerASCII(digits[i])) {
+			return "", false
+		}
+	}
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return "", false
+	}
+	return c.EncodeToken(sign + value.String())
+}
+
+// literalBase detects the base prefix of a literal and returns the digits after it.
+func literalBase(literal string) (base int, digits string) {
+	if len(literal) > 2 && literal[0] == digit0 {
+		switch literal[1] {
+		case 'x', 'X':
+			return 16, literal[2:]
+		case 'o', 'O':
+			return 8, literal[2:]
+		case 'b', 'B':
+			return 2, literal[2:]
+		}
+	}
+	return 10, literal
+}
+
+// isValidDigitSeparation checks that underscores only appear between two digits, or right after a base
+// prefix, as Go requires.
+func isValidDigitSeparation(digits string, prefixed bool) bool {
+	if digits == "" {
+		return false
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] != digitSeparator {
+			continue
+		}
+		if i == len(digits)-1 || !isDigit(toLowerASCII(digits[i+1])) {
+			return false
+		}
+		if i == 0 {
+			if !prefixed {
+				return false
+			}
+			continue
+		}
+		if !isDigit(toLowerASCII(digits[i-1])) {
+			return false
+		}
+	}
+	return true
+}
+
+func toLowerASCII(ch byte) byte {
+	if ch >= 'A' && ch <= 'Z' {
+		return ch + ('a' - 'A')
+	}
+	return ch
+}
+
+// LiteralRecognizer recognizes Go style number literals in mixed text, like "0x1F", "0b1010" or
+// "1_000_000", and encodes them with EncodeLiteral, so "register 0x1F" sorts before "register 0x20"
+// and "limit 1_000_000" after "limit 999". Plain decimal numbers are left to the normal processing.
+type LiteralRecognizer struct{}
+
+// Recognize implements the Recognizer interface.
+func (LiteralRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	if !isDecimalDigit(input[pos]) || (pos > 0 && (isWordByte(input[pos-1]) || input[pos-1] == decimalPoint)) {
+		return 0, "", false
+	}
+
+	end := pos
+	for end < len(input) && (isASCIILetter(input[end]) || isDecimalDigit(input[end]) || input[end] == digitSeparator) {
+		end++
+	}
+	literal := input[pos:end]
+	base, _ := literalBase(literal)
+	if base == 10 && strings.IndexByte(literal, digitSeparator) < 0 {
+		return 0, "", false
+	}
+
+	token, ok = c.EncodeLiteral(literal)
+	if !ok {
+		return 0, "", false
+	}
+	return end - pos, token, true
+}
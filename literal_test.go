@@ -0,0 +1,101 @@
+package conust
+
+import (
+	"testing"
+)
+
+func TestEncodeLiteral(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		decimal string
+	}{
+		{name: "decimal", input: "31", decimal: "31"},
+		{name: "hexadecimal", input: "0x1F", decimal: "31"},
+		{name: "hexadecimal lower case", input: "0x1f", decimal: "31"},
+		{name: "octal", input: "0o37", decimal: "31"},
+		{name: "binary", input: "0B11111", decimal: "31"},
+		{name: "underscores", input: "1_000_000", decimal: "1000000"},
+		{name: "underscore after prefix", input: "0x_ff_ff", decimal: "65535"},
+		{name: "negative", input: "-0b1010", decimal: "-10"},
+		{name: "fraction", input: "+1_000.000_1", decimal: "1000.0001"},
+		{name: "leading zero", input: "0755", decimal: "755"},
+		{name: "huge", input: "0xffffffffffffffffffffffff", decimal: "79228162514264337593543950335"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			encoded, ok := c.EncodeLiteral(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			expected, _ := c.EncodeToken(i.decimal)
+			if encoded != expected {
+				t.Fatalf("encoding expected %q got %q", expected, encoded)
+			}
+		})
+	}
+}
+
+func TestEncodeLiteral_Failure(t *testing.T) {
+	testCases := []string{"", "_1", "1_", "1__0", "0x", "0x_", "0x1g", "0b102", "0o8", "0x-1", "1_.5", "1a", "--1"}
+
+	c := new(Codec)
+	for _, input := range testCases {
+		if encoded, ok := c.EncodeLiteral(input); ok || encoded != "" {
+			t.Fatalf("encoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestLiteralRecognizer(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{name: "hexadecimal", input: "register 0x1F", output: "register 7231"},
+		{name: "binary", input: "mask 0b1010 set", output: "mask 721 set"},
+		{name: "underscores", input: "limit 1_000_000", output: "limit 771"},
+		{name: "plain number", input: "limit 1000", output: "limit 741"},
+		{name: "invalid literal", input: "0xZZ", output: "5 xZZ"},
+		{name: "inside a word", input: "r0x1F", output: "r 5 x 711 F"},
+	}
+
+	c := &Codec{Recognizers: []Recognizer{LiteralRecognizer{}}}
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.output {
+				t.Fatalf("output expected %q got %q", i.output, encoded)
+			}
+		})
+	}
+}
+
+func TestLiteralRecognizer_Order(t *testing.T) {
+	ordered := []string{
+		"register 0b1",
+		"register 0x2",
+		"register 0o7",
+		"register 9",
+		"register 0x1F",
+		"register 0x20",
+		"register 1_000",
+		"register 0xFFFF",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{LiteralRecognizer{}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
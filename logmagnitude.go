@@ -0,0 +1,259 @@
+package conust
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The tokens with a logarithmic magnitude have the sign bytes of the Conust format shifted from '3'...'7'
+// to 'F'...'J', so they cannot be mistaken for the ones of EncodeToken or EncodeDense.
+const logSignOffset = 'F' - '3'
+
+// maxLogDecodedMagnitude limits the magnitude DecodeTokenLog writes out in full, so that decoding a
+// malformed input cannot allocate an arbitrary amount of memory. DecodeScientific has no such limit.
+const maxLogDecodedMagnitude = 1 << 24
+
+// maxScientificExponent keeps the magnitude computed by EncodeScientific far from overflowing, even where
+// int has 32 bits.
+const maxScientificExponent = 1 << 30
+
+// EncodeTokenLog turns the input number into a string that sorts like the output of EncodeToken, but
+// writes the magnitude as the count of its base 36 digits followed by the digits, instead of one 'z' for
+// every 34 of it. So the length of the magnitude grows with its logarithm, which keeps numbers with a huge
+// number of leading or trailing zeros short.
+func (c *Codec) EncodeTokenLog(input string) (out string, ok bool) {
+	token, ok := c.EncodeToken(input)
+	if !ok {
+		return "", false
+	}
+	return c.tokenToLog(token, 0)
+}
+
+// DecodeTokenLog turns a string generated by EncodeTokenLog back into the number, written out in full like
+// DecodeToken does. Numbers with a magnitude above 16777215 are rejected, use DecodeScientific for those.
+func (c *Codec) DecodeTokenLog(input string) (out string, ok bool) {
+	if input == "" {
+		return "", true
+	}
+	positive, magnitudePositive, magnitude, digits, ok := c.parseLogToken(input)
+	if !ok {
+		return "", false
+	}
+	if digits == "" {
+		return zeroInput, true
+	}
+	if magnitude > maxLogDecodedMagnitude {
+		return "", false
+	}
+
+	c.builder.Reset()
+	c.builder.WriteByte(c.encodeSign(positive, magnitudePositive))
+	c.writeMagnitude(positive, magnitudePositive, magnitude)
+	c.builder.WriteString(digits)
+	if !positive {
+		c.builder.WriteByte(negativeNumberTerminator)
+	}
+	return c.DecodeToken(c.builder.String())
+}
+
+// EncodeScientific is like EncodeTokenLog, but for decimal numbers in scientific notation, like "1.5e-300"
+// or "-2E+1000000". The exponent is optional, and it moves the magnitude without the number ever being
+// written out, so the length of the output only depends on the significant digits and on the logarithm of
+// the exponent. The outputs sort together with the ones of EncodeTokenLog for decimal numbers. Exponents
+// beyond plus or minus 1073741824 are rejected.
+func (c *Codec) EncodeScientific(input string) (out string, ok bool) {
+	if input == "" {
+		return "", true
+	}
+
+	mantissa, exponent := input, 0
+	if pos := strings.IndexAny(input, "eE"); pos >= 0 {
+		mantissa = input[:pos]
+		parsed, err := strconv.ParseInt(input[pos+1:], 10, 64)
+		if err != nil || parsed > maxScientificExponent || parsed < -maxScientificExponent {
+			return "", false
+		}
+		exponent = int(parsed)
+	}
+	if mantissa == "" {
+		return "", false
+	}
+	for i := 0; i < len(mantissa); i++ {
+		if isDigit(mantissa[i]) && !isDecimalDigit(mantissa[i]) {
+			return "", false
+		}
+	}
+
+	token, ok := c.EncodeToken(mantissa)
+	if !ok {
+		return "", false
+	}
+	return c.tokenToLog(token, exponent)
+}
+
+// DecodeScientific turns a string generated by EncodeScientific, or by EncodeTokenLog for a decimal number,
+// back into the number in scientific notation with a single digit before the decimal point, like "1.5e-300".
+func (c *Codec) DecodeScientific(input string) (out string, ok bool) {
+	if input == "" {
+		return "", true
+	}
+	positive, magnitudePositive, magnitude, digits, ok := c.parseLogToken(input)
+	if !ok {
+		return "", false
+	}
+	if digits == "" {
+		return zeroInput, true
+	}
+
+	var b strings.Builder
+	b.Grow(len(digits) + 24)
+	if !positive {
+		b.WriteByte(minusByte)
+	}
+	for i := 0; i < len(digits); i++ {
+		digit := digits[i]
+		if !positive {
+			digit = reverseDigit(digit)
+		}
+		if !isDecimalDigit(digit) {
+			return "", false
+		}
+		if i == 1 {
+			b.WriteByte(decimalPoint)
+		}
+		b.WriteByte(digit)
+	}
+
+	exponent := magnitude
+	if !magnitudePositive {
+		exponent = -magnitude
+	}
+	b.WriteByte('e')
+	b.WriteString(strconv.Itoa(exponent - 1))
+	return b.String(), true
+}
+
+// tokenToLog rewrites a token of EncodeToken with a logarithmic magnitude, multiplying the number by
+// 10 to the power of exponent.
+func (c *Codec) tokenToLog(token string, exponent int) (out string, ok bool) {
+	if token == "" {
+		return "", true
+	}
+	if token == zeroOutput {
+		return string(zeroOutput[0] + logSignOffset), true
+	}
+
+	positive, magnitudePositive, _ := c.decodeSigns(token)
+	magnitude, significantPartPos, _ := c.decodeMagnitude(token, positive, magnitudePositive)
+	if exponent != 0 {
+		// The position of the first significant digit relative to the decimal point, which is 1 for
+		// "1" and 0 for "0.1", is what the exponent moves.
+		position := magnitude
+		if !magnitudePositive {
+			position = -magnitude
+		}
+		position += exponent
+		magnitudePositive = position > 0
+		magnitude = position
+		if !magnitudePositive {
+			magnitude = -position
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(token) + 12)
+	b.WriteByte(c.encodeSign(positive, magnitudePositive) + logSignOffset)
+	writeLogMagnitude(&b, magnitude, positive != magnitudePositive)
+	b.WriteString(token[significantPartPos:])
+	return b.String(), true
+}
+
+// parseLogToken splits a token with a logarithmic magnitude into its parts. The digits are returned as
+// they are in the token, inverted for negative numbers but without the terminator, and they are empty
+// for zero.
+func (c *Codec) parseLogToken(input string) (positive bool, magnitudePositive bool, magnitude int, digits string, ok bool) {
+	sign := input[0] - logSignOffset
+	if sign == zeroOutput[0] {
+		return true, true, 0, "", len(input) == 1
+	}
+	positive, magnitudePositive, ok = c.decodeSigns(string(sign))
+	if !ok {
+		return false, false, 0, "", false
+	}
+
+	end := len(input)
+	if !positive {
+		if input[end-1] != negativeNumberTerminator {
+			return false, false, 0, "", false
+		}
+		end--
+	}
+	magnitude, pos, ok := readLogMagnitude(input[:end], 1, positive != magnitudePositive)
+	if !ok || pos == end || (magnitudePositive && magnitude == 0) {
+		return false, false, 0, "", false
+	}
+
+	digits = input[pos:end]
+	for i := 0; i < len(digits); i++ {
+		if !isDigit(digits[i]) {
+			return false, false, 0, "", false
+		}
+	}
+	insignificant := digit0
+	if !positive {
+		insignificant = reverseDigit(digit0)
+	}
+	if digits[0] == insignificant || digits[len(digits)-1] == insignificant {
+		return false, false, 0, "", false
+	}
+	return positive, magnitudePositive, magnitude, digits, true
+}
+
+// writeLogMagnitude writes the number of base 36 digits of the magnitude as a single digit, followed by
+// the digits, all of them reversed if reverse is set. Since a longer magnitude is always a larger one, and
+// no magnitude is the prefix of another, they sort by value.
+func writeLogMagnitude(b *strings.Builder, magnitude int, reverse bool) {
+	digits := strconv.FormatInt(int64(magnitude), 36)
+	write := func(digit byte) {
+		if reverse {
+			digit = reverseDigit(digit)
+		}
+		b.WriteByte(digit)
+	}
+	write(intToDigit(len(digits)))
+	for i := 0; i < len(digits); i++ {
+		write(digits[i])
+	}
+}
+
+func readLogMagnitude(input string, pos int, reverse bool) (magnitude int, end int, ok bool) {
+	if pos >= len(input) || !isDigit(input[pos]) {
+		return 0, 0, false
+	}
+	length := digitToInt(input[pos])
+	if reverse {
+		length = reversedDigitToInt(input[pos])
+	}
+	pos++
+	if length == 0 || pos+length > len(input) {
+		return 0, 0, false
+	}
+
+	digits := []byte(input[pos : pos+length])
+	for i, digit := range digits {
+		if !isDigit(digit) {
+			return 0, 0, false
+		}
+		if reverse {
+			digits[i] = reverseDigit(digit)
+		}
+	}
+	if length > 1 && digits[0] == digit0 {
+		return 0, 0, false
+	}
+	value, err := strconv.ParseInt(string(digits), 36, strconv.IntSize)
+	if err != nil {
+		return 0, 0, false
+	}
+	return int(value), pos + length, true
+}
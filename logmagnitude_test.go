@@ -0,0 +1,164 @@
+package conust
+
+import (
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncodeTokenLog(t *testing.T) {
+	testCases := []struct {
+		input   string
+		encoded string
+		decoded string
+	}{
+		{input: "", encoded: "", decoded: ""},
+		{input: "0", encoded: "H", decoded: "0"},
+		{input: "1", encoded: "J111", decoded: "1"},
+		{input: "-1", encoded: "Fyyy~", decoded: "-1"},
+		{input: "-123", encoded: "Fywyxw~", decoded: "-123"},
+		{input: "0.00125", encoded: "Iyx125", decoded: "0.00125"},
+		{input: "-0.5", encoded: "G10u~", decoded: "-0.5"},
+		{input: "+1200", encoded: "J1412", decoded: "1200"},
+		{input: "zz", encoded: "J12zz", decoded: "zz"},
+		{input: "1" + strings.Repeat("0", 1000), encoded: "J2rt1", decoded: "1" + strings.Repeat("0", 1000)},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.encoded, func(t *testing.T) {
+			encoded, ok := c.EncodeTokenLog(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeTokenLog(encoded)
+			if !ok || decoded != i.decoded {
+				t.Fatalf("decoding expected %q got %q", i.decoded, decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeScientific(t *testing.T) {
+	testCases := []struct {
+		input   string
+		encoded string
+		decoded string
+	}{
+		{input: "", encoded: "", decoded: ""},
+		{input: "0e5", encoded: "H", decoded: "0"},
+		{input: "123", encoded: "J13123", decoded: "1.23e2"},
+		{input: "2.50e+3", encoded: "J1425", decoded: "2.5e3"},
+		{input: "0.1e1", encoded: "J111", decoded: "1e0"},
+		{input: "1e100000", encoded: "J4255t1", decoded: "1e100000"},
+		{input: "-1.5E-300", encoded: "G28byu~", decoded: "-1.5e-300"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.input, func(t *testing.T) {
+			encoded, ok := c.EncodeScientific(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeScientific(encoded)
+			if !ok || decoded != i.decoded {
+				t.Fatalf("decoding expected %q got %q", i.decoded, decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeScientific_ExponentBound(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []string{"1e1073741824", "-1.5e-1073741824"} {
+		encoded, ok := c.EncodeScientific(input)
+		if !ok {
+			t.Fatalf("encoding failed for %q", input)
+		}
+		if decoded, ok := c.DecodeScientific(encoded); !ok || decoded != input {
+			t.Fatalf("decoding expected %q got %q", input, decoded)
+		}
+	}
+	for _, input := range []string{"1e1073741825", "-1.5e-1073741825"} {
+		if _, ok := c.EncodeScientific(input); ok {
+			t.Fatalf("encoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestEncodeScientific_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []string{"e5", "1e", "1e5.5", "a1e2", "1e99999999999999", "1e2000000000", "1.2.3e4"} {
+		if _, ok := c.EncodeScientific(input); ok {
+			t.Fatalf("encoding should have failed for %q", input)
+		}
+	}
+	for _, input := range []string{"7", "J", "J1", "J11", "J01", "J20111", "J1110", "J101", "Fyyy", "H1", "J1!1"} {
+		if _, ok := c.DecodeTokenLog(input); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+	huge, _ := c.EncodeScientific("1e1000000000")
+	if _, ok := c.DecodeTokenLog(huge); ok {
+		t.Fatal("decoding should have failed for a magnitude too large to write out")
+	}
+	if _, ok := c.DecodeScientific("J12zz"); ok {
+		t.Fatal("decoding should have failed for a number that is not decimal")
+	}
+}
+
+func TestEncodeTokenLog_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 20000; i++ {
+		a := strconv.FormatFloat(rand.NormFloat64()*1e6, 'f', rand.Intn(8), 64) + strings.Repeat("0", rand.Intn(80))
+		b := strconv.FormatFloat(rand.NormFloat64()*1e6, 'f', rand.Intn(8), 64) + strings.Repeat("0", rand.Intn(80))
+		tokenA, _ := c.EncodeToken(a)
+		tokenB, _ := c.EncodeToken(b)
+		logA, ok := c.EncodeTokenLog(a)
+		if !ok {
+			t.Fatalf("encoding failed for %q", a)
+		}
+		logB, _ := c.EncodeTokenLog(b)
+		if compareStrings(tokenA, tokenB) != compareStrings(logA, logB) {
+			t.Fatalf("%s and %s sort differently (%q and %q)", a, b, logA, logB)
+		}
+	}
+}
+
+func TestEncodeScientific_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	randomNumber := func() string {
+		mantissa := strconv.FormatFloat(rand.NormFloat64()*1000, 'f', rand.Intn(4), 64)
+		return mantissa + "e" + strconv.Itoa(rand.Intn(800)-400)
+	}
+	for i := 0; i < 20000; i++ {
+		a, b := randomNumber(), randomNumber()
+		ratA, _ := new(big.Rat).SetString(a)
+		ratB, _ := new(big.Rat).SetString(b)
+		encodedA, ok := c.EncodeScientific(a)
+		if !ok {
+			t.Fatalf("encoding failed for %q", a)
+		}
+		encodedB, _ := c.EncodeScientific(b)
+		if ratA.Cmp(ratB) != compareStrings(encodedA, encodedB) {
+			t.Fatalf("%s and %s sort differently (%q and %q)", a, b, encodedA, encodedB)
+		}
+
+		decoded, ok := c.DecodeScientific(encodedA)
+		ratDecoded, _ := new(big.Rat).SetString(decoded)
+		if !ok || ratDecoded == nil || ratDecoded.Cmp(ratA) != 0 {
+			t.Fatalf("decoding %q expected %s got %q", encodedA, a, decoded)
+		}
+	}
+}
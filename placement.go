@@ -0,0 +1,68 @@
+package conust
+
+import (
+	"strings"
+)
+
+// NumberPlacement decides where EncodeMixedText puts numbers relative to the text around them.
+type NumberPlacement int
+
+const (
+	// NumbersInText is the default placement: a number is surrounded by spaces and sorts by the first
+	// byte of its token, after space and some punctuation like "!" and "(" but before letters.
+	NumbersInText NumberPlacement = iota
+	// NumbersFirst makes numbers sort before any text, then punctuation, then letters,
+	// so "10 items" sorts before "(draft)" which sorts before "apple".
+	NumbersFirst
+	// NumbersLast makes numbers sort after any text, so "apple" sorts before "10 items".
+	// Punctuation still sorts before letters.
+	NumbersLast
+)
+
+const (
+	// numbersFirstMarker precedes every token when numbers go first, it is below all escaped text.
+	numbersFirstMarker byte = 0x01
+	// numbersLastMarker precedes every token when numbers go last, it never appears in valid UTF-8.
+	numbersLastMarker byte = 0xff
+	// punctuationMarker precedes every ASCII punctuation byte of the text when numbers are not in the
+	// text, grouping punctuation between whitespace and letters.
+	punctuationMarker byte = '!'
+	// lowByteEscape precedes text bytes up to itself, so that they sort after numbersFirstMarker.
+	lowByteEscape byte = 0x02
+	// highByteEscape precedes text bytes from itself up, so that they sort before numbersLastMarker.
+	// Neither of them appears in valid UTF-8.
+	highByteEscape byte = 0xfe
+)
+
+// tokenMarker returns the byte written in front of every token, and false for NumbersInText, which
+// separates tokens with spaces instead.
+func (p NumberPlacement) tokenMarker() (marker byte, ok bool) {
+	switch p {
+	case NumbersFirst:
+		return numbersFirstMarker, true
+	case NumbersLast:
+		return numbersLastMarker, true
+	}
+	return 0, false
+}
+
+// writePlacedText writes text between tokens, prefixing ASCII punctuation with punctuationMarker, and the
+// bytes that could be confused with the token markers with an escape byte that keeps their order.
+func writePlacedText(b *strings.Builder, text string) {
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case isASCIIPunctuation(ch):
+			b.WriteByte(punctuationMarker)
+		case ch <= lowByteEscape:
+			b.WriteByte(lowByteEscape)
+		case ch >= highByteEscape:
+			b.WriteByte(highByteEscape)
+		}
+		b.WriteByte(ch)
+	}
+}
+
+func isASCIIPunctuation(ch byte) bool {
+	return ch > ' ' && ch < 0x7f && !isASCIILetter(ch) && !isDecimalDigit(ch)
+}
@@ -0,0 +1,75 @@
+package conust
+
+import (
+	"testing"
+)
+
+func TestNumberPlacement(t *testing.T) {
+	testCases := []struct {
+		name      string
+		placement NumberPlacement
+		input     string
+		output    string
+	}{
+		{name: "in text", placement: NumbersInText, input: "A300 (b)", output: "A 733 (b)"},
+		{name: "first", placement: NumbersFirst, input: "A300 (b)", output: "A\x01733 !(b!)"},
+		{name: "first only number", placement: NumbersFirst, input: "12", output: "\x017212"},
+		{name: "first after space", placement: NumbersFirst, input: "a 1", output: "a \x01711"},
+		{name: "last", placement: NumbersLast, input: "x-1", output: "x!-\xff711"},
+		{name: "last no numbers", placement: NumbersLast, input: "a.b c", output: "a!.b c"},
+		{name: "first low bytes", placement: NumbersFirst, input: "\x00\t1", output: "\x02\x00\t\x01711"},
+		{name: "last high bytes", placement: NumbersLast, input: "1\xff", output: "\xff711 \xfe\xff"},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			c := &Codec{NumberPlacement: i.placement}
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.output {
+				t.Fatalf("output expected %q got %q", i.output, encoded)
+			}
+		})
+	}
+}
+
+func TestNumberPlacement_Order(t *testing.T) {
+	testCases := []struct {
+		name      string
+		placement NumberPlacement
+		ordered   []string
+	}{
+		{
+			name:      "first",
+			placement: NumbersFirst,
+			ordered:   []string{"2 apples", "10 apples", " apples", "!important", "(draft)", "_tmp", "apple 2", "apple 10", "apple pie", "apple!", "banana"},
+		},
+		{
+			name:      "last",
+			placement: NumbersLast,
+			ordered:   []string{" apples", "!important", "(draft)", "_tmp", "apple pie", "apple 2", "apple 10", "apple!", "banana", "2 apples", "10 apples"},
+		},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			c := &Codec{NumberPlacement: i.placement}
+			prev, _ := c.EncodeMixedText(i.ordered[0])
+			for j := 1; j < len(i.ordered); j++ {
+				encoded, _ := c.EncodeMixedText(i.ordered[j])
+				if prev >= encoded {
+					t.Fatalf("%q does not sort before %q", i.ordered[j-1], i.ordered[j])
+				}
+				prev = encoded
+			}
+		})
+	}
+}
+
+func TestNumberPlacement_Reference(t *testing.T) {
+	for _, placement := range []NumberPlacement{NumbersFirst, NumbersLast} {
+		testMixedTextOrder(t, &Codec{NumberPlacement: placement})
+	}
+}
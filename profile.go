@@ -0,0 +1,315 @@
+package conust
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MixedTextProfile selects the ordering rules that EncodeMixedTextProfile reproduces.
+type MixedTextProfile int
+
+const (
+	// ProfileDefault produces the same output as EncodeMixedText.
+	ProfileDefault MixedTextProfile = iota
+
+	// ProfileVersionSort reproduces the ordering of GNU sort -V and ls -v (the gnulib filevercmp function):
+	// "" < "." < ".." < hidden names < other names. Names are compared without their file suffix
+	// first and with it only to break ties. Decimal digit runs compare by value, leading zeros ignored.
+	// In the remaining text "~" sorts before everything (even the end of the name), then come ASCII
+	// letters case sensitively, then every other byte. Names that are still equal are ordered bytewise.
+	ProfileVersionSort
+
+	// ProfileWindows reproduces the ordering of Windows Explorer (the StrCmpLogicalW function):
+	// punctuation sorts before decimal digit runs, which compare by value, and those sort before letters.
+	// The text is compared case insensitively. Names that are still equal (like "01"
+	// and "1") are ordered bytewise.
+	ProfileWindows
+
+	// ProfileFinder reproduces the ordering of the macOS Finder (localizedStandardCompare):
+	// whitespace < punctuation < symbols < numbers < letters, decimal digit runs compare by value, and
+	// letters compare ignoring case and diacritics. ASCII punctuation and symbols follow the ICU root
+	// collation, which Finder uses, so "_" sorts before "-" and "$" after "+". Ties are broken by
+	// preferring unaccented letters, then lower case letters, then bytewise order.
+	ProfileFinder
+)
+
+// Bytes structuring the profile keys. They are all below the digits, so they never compare against
+// the inside of a token.
+const profileTieBreak byte = 0x00
+const profileTokenEnd byte = 0x01
+
+const versionClassDot byte = 0x01
+const versionClassDotDot byte = 0x02
+const versionClassHidden byte = 0x03
+const versionClassRegular byte = 0x04
+
+const versionTilde byte = 0x02
+const versionPartEnd byte = 0x03
+const versionLetter byte = 0x04
+const versionOther byte = 0x05
+
+const windowsPunctuation byte = 0x02
+const windowsNumber byte = 0x03
+const windowsLetter byte = 0x04
+
+const finderSpace byte = 0x02
+const finderPunctuation byte = 0x03
+const finderSymbol byte = 0x04
+const finderNumber byte = 0x05
+const finderLetter byte = 0x06
+
+// finderASCIIOrder lists the ASCII punctuation and symbols in the order of the ICU root collation.
+const finderASCIIOrder = "_-,;:!?.'\"()[]{}@*/\\&#%`^+<=>|~$"
+
+const finderPlain byte = 0x01
+const finderMarked byte = 0x02
+
+// EncodeMixedTextProfile turns the input into a string whose bytewise ordering matches the ordering
+// the selected profile describes. Except for ProfileDefault the output is a sort key only, it is not
+// meant to be displayed, and it can contain control characters.
+func (c *Codec) EncodeMixedTextProfile(input string, profile MixedTextProfile) (out string, ok bool) {
+	switch profile {
+	case ProfileDefault:
+		return c.EncodeMixedText(input)
+	case ProfileVersionSort:
+		return c.encodeVersionSortKey(input), true
+	case ProfileWindows:
+		return c.encodeWindowsKey(input), true
+	case ProfileFinder:
+		return c.encodeFinderKey(input), true
+	default:
+		return "", false
+	}
+}
+
+func (c *Codec) encodeVersionSortKey(input string) string {
+	if input == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.Grow(3*len(input) + 8)
+	switch {
+	case input == ".":
+		b.WriteByte(versionClassDot)
+	case input == "..":
+		b.WriteByte(versionClassDotDot)
+	case input[0] == '.':
+		b.WriteByte(versionClassHidden)
+	default:
+		b.WriteByte(versionClassRegular)
+	}
+
+	c.writeVersionParts(&b, input[:versionPrefixLength(input)])
+	c.writeVersionParts(&b, input)
+	b.WriteString(input)
+	return b.String()
+}
+
+// writeVersionParts writes every text run followed by a part end marker and every digit run as a token.
+// Text runs are always closed by a number (zero if missing), so that the end of the input compares
+// like the start of a digit run, as it does in filevercmp.
+func (c *Codec) writeVersionParts(b *strings.Builder, input string) {
+	expectNumber := false
+	scanDecimalRuns(input, func(start int, end int, number bool) {
+		if !number {
+			if expectNumber {
+				c.writeProfileToken(b, zeroInput)
+			}
+			for i := start; i < end; i++ {
+				ch := input[i]
+				switch {
+				case ch == '~':
+					b.WriteByte(versionTilde)
+				case isASCIILetter(ch):
+					b.WriteByte(versionLetter)
+					b.WriteByte(ch)
+				default:
+					b.WriteByte(versionOther)
+					b.WriteByte(ch)
+				}
+			}
+			b.WriteByte(versionPartEnd)
+			expectNumber = true
+			return
+		}
+		if !expectNumber {
+			b.WriteByte(versionPartEnd)
+		}
+		c.writeProfileToken(b, input[start:end])
+		expectNumber = false
+	})
+	if expectNumber {
+		c.writeProfileToken(b, zeroInput)
+	}
+	b.WriteByte(versionPartEnd)
+}
+
+// versionPrefixLength returns the length of the input without its file suffix, the longest match of
+// (\.[A-Za-z~][A-Za-z0-9~]*)*$. Like in GNU sort -V, the suffix may start at the first character, so all of
+// a hidden name like ".bashrc" is a suffix.
+func versionPrefixLength(input string) int {
+	prefixLength := 0
+	for i := 0; ; {
+		for i+1 < len(input) && input[i] == '.' && (isASCIILetter(input[i+1]) || input[i+1] == '~') {
+			for i += 2; i < len(input) && (isASCIILetter(input[i]) || isDecimalDigit(input[i]) || input[i] == '~'); i++ {
+			}
+		}
+		if i == len(input) {
+			return prefixLength
+		}
+		i++
+		prefixLength = i
+	}
+}
+
+func (c *Codec) encodeWindowsKey(input string) string {
+	var b strings.Builder
+	b.Grow(2*len(input) + 4)
+
+	scanDecimalRuns(input, func(start int, end int, number bool) {
+		if number {
+			b.WriteByte(windowsNumber)
+			c.writeProfileToken(&b, input[start:end])
+			return
+		}
+		for i := start; i < end; {
+			r, size := utf8.DecodeRuneInString(input[i:])
+			if unicode.IsLetter(r) {
+				b.WriteByte(windowsLetter)
+			} else {
+				b.WriteByte(windowsPunctuation)
+			}
+			writeFoldedRune(&b, input[i:i+size], unicode.ToLower(r))
+			i += size
+		}
+	})
+
+	b.WriteByte(profileTieBreak)
+	b.WriteString(input)
+	return b.String()
+}
+
+func (c *Codec) encodeFinderKey(input string) string {
+	var b strings.Builder
+	var marks, cases []byte
+	b.Grow(2*len(input) + 4)
+
+	scanDecimalRuns(input, func(start int, end int, number bool) {
+		if number {
+			b.WriteByte(finderNumber)
+			c.writeProfileToken(&b, input[start:end])
+			return
+		}
+		for i := start; i < end; {
+			r, size := utf8.DecodeRuneInString(input[i:])
+			if unicode.Is(unicode.Mn, r) {
+				// A combining mark of a decomposed letter, as macOS file systems store them, only
+				// counts in the tie break, like the diacritic of a precomposed letter.
+				if n := len(marks); n > 0 {
+					marks[n-1] = finderMarked
+				} else {
+					marks = append(marks, finderMarked)
+				}
+				i += size
+				continue
+			}
+			lower := unicode.ToLower(r)
+			base := stripDiacritic(lower)
+			switch {
+			case unicode.IsSpace(r):
+				b.WriteByte(finderSpace)
+			case unicode.IsPunct(r):
+				b.WriteByte(finderPunctuation)
+			case unicode.IsSymbol(r):
+				b.WriteByte(finderSymbol)
+			default:
+				b.WriteByte(finderLetter)
+			}
+			if rank := strings.IndexByte(finderASCIIOrder, input[i]); size == 1 && rank >= 0 {
+				// The ranks are below all other characters, so ASCII comes first like in ICU.
+				b.WriteByte(byte(rank))
+			} else {
+				writeFoldedRune(&b, input[i:i+size], base)
+			}
+			marks = append(marks, finderFlag(base != lower))
+			cases = append(cases, finderFlag(lower != r))
+			i += size
+		}
+	})
+
+	b.WriteByte(profileTieBreak)
+	b.Write(marks)
+	b.WriteByte(profileTieBreak)
+	b.Write(cases)
+	b.WriteByte(profileTieBreak)
+	b.WriteString(input)
+	return b.String()
+}
+
+func finderFlag(set bool) byte {
+	if set {
+		return finderMarked
+	}
+	return finderPlain
+}
+
+// writeProfileToken writes the token of a decimal digit run and closes it with a byte that is lower than
+// any digit, so a token never compares against the bytes following another token.
+func (c *Codec) writeProfileToken(b *strings.Builder, digits string) {
+	encoded, _ := c.EncodeToken(digits)
+	b.WriteString(encoded)
+	b.WriteByte(profileTokenEnd)
+}
+
+// writeFoldedRune writes the folded version of a rune, or the original bytes if they are not valid UTF-8.
+func writeFoldedRune(b *strings.Builder, original string, folded rune) {
+	if folded == utf8.RuneError {
+		b.WriteString(original)
+		return
+	}
+	b.WriteRune(folded)
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+var diacriticFolds = [...]struct {
+	marked string
+	base   rune
+}{
+	{"àáâãäåāăą", 'a'},
+	{"çćĉċč", 'c'},
+	{"ďđ", 'd'},
+	{"èéêëēĕėęě", 'e'},
+	{"ĝğġģ", 'g'},
+	{"ĥħ", 'h'},
+	{"ìíîïĩīĭįı", 'i'},
+	{"ĵ", 'j'},
+	{"ķ", 'k'},
+	{"ĺļľŀł", 'l'},
+	{"ñńņňŉ", 'n'},
+	{"òóôõöøōŏő", 'o'},
+	{"ŕŗř", 'r'},
+	{"śŝşš", 's'},
+	{"ţťŧ", 't'},
+	{"ùúûüũūŭůűų", 'u'},
+	{"ŵ", 'w'},
+	{"ýÿŷ", 'y'},
+	{"źżž", 'z'},
+}
+
+// stripDiacritic maps lower case Latin-1 and Latin Extended-A letters to their unaccented base letter.
+func stripDiacritic(r rune) rune {
+	if r < 0xe0 || r > 0x17f {
+		return r
+	}
+	for _, fold := range diacriticFolds {
+		if strings.ContainsRune(fold.marked, r) {
+			return fold.base
+		}
+	}
+	return r
+}
@@ -0,0 +1,158 @@
+package conust
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEncodeMixedTextProfile_Golden(t *testing.T) {
+	testCases := []struct {
+		name    string
+		profile MixedTextProfile
+		ordered []string
+	}{
+		{
+			name:    "version sort numbers",
+			profile: ProfileVersionSort,
+			ordered: []string{"a1", "a2", "a9", "a010", "a10", "a11", "a100"},
+		},
+		{
+			name:    "version sort release candidates",
+			profile: ProfileVersionSort,
+			// printf '%s\n' 1.0 1.0~rc1 1.0.1 1.0~rc2 1.0~ | sort -V
+			ordered: []string{"1.0~", "1.0~rc1", "1.0~rc2", "1.0", "1.0.1"},
+		},
+		{
+			name:    "version sort hidden files",
+			profile: ProfileVersionSort,
+			// ls -av
+			ordered: []string{".", "..", ".bashrc", ".config", "Makefile", "README", "a", "b"},
+		},
+		{
+			name:    "version sort letters before punctuation",
+			profile: ProfileVersionSort,
+			ordered: []string{"a", "a1", "aA", "ab", "a-b", "a_b"},
+		},
+		{
+			name:    "version sort suffixes",
+			profile: ProfileVersionSort,
+			// the suffix is only compared when the names without it are equal
+			ordered: []string{"hello-8.txt", "hello-8.2.txt", "hello-8.10.txt", "hello-9", "hello-9.tar", "hello-9.tar.gz"},
+		},
+		{
+			name:    "version sort output",
+			profile: ProfileVersionSort,
+			// the output of LC_ALL=C sort -V (GNU coreutils 9.1)
+			ordered: []string{
+				".Z9", ".a", "..a", ".09-.", "..9.__", ".._", "._Z0a9", "._aZ", "._b", "~~_.", "~", "~9", "~9--", "~Z-",
+				"~aZZ.", "~a._~0", "~-.~", "~--.", "~-_9", "~._.-_", "~_", "0~~", "0", "0Z~0", "0a", "0aZZ0", "0aa0_9",
+				"00aa_", "0a_9aa", "0.9~x.y", "9~90", "9~_", "09", "9", "9Z", "9a", "9aZ~9", "090", "90Z~", "99-~",
+				"99-9ZZ", "99_a0-", "Z~9.0-", "Z", "Z0_~-Z", "Z0_Z.Z", "Z9~", "Zaa-.", "Z-a", "Z.Z_a", "Z.-9~0", "Z_9.",
+				"a~~_9", "a~", "a~0.", "a~Z", "a", "a.ZZ", "a.tar", "a.tar.gz", "a0.9.txt", "a00.9.txt", "a1.tar.gz",
+				"a1.0~rc1.tar", "aZa", "aa0aZ-", "aa.0..", "aa_9.", "aa_a~", "a.9-~Z", "x.~a", "-~~~", "-", "-.~",
+				"-9aZZa", "-Z0", "-Z0._", "-Za.", "-a~_a", "--.~~~", "--00Z", "-..a._", "-_.9a", "_~a~", "_9~9", "_9-a_Z",
+				"_9_9a9", "_-9", "_-9_", "__", "__.ZZ", "__9",
+			},
+		},
+		{
+			name:    "windows numbers",
+			profile: ProfileWindows,
+			ordered: []string{"File1.txt", "File2.txt", "file3.txt", "File10.txt", "File010a.txt", "File10b.txt"},
+		},
+		{
+			name:    "windows leading zeros and case",
+			profile: ProfileWindows,
+			ordered: []string{"01", "1", "1a", "1B", "1c", "A", "a"},
+		},
+		{
+			name:    "windows punctuation before digits before letters",
+			profile: ProfileWindows,
+			ordered: []string{"(a", "-a", "_a", "2", "10", "a", "a_", "a1", "ab"},
+		},
+		{
+			name:    "finder numbers",
+			profile: ProfileFinder,
+			ordered: []string{"IMG_9.jpg", "IMG_10.jpg", "img_11.jpg", "IMG_100.jpg"},
+		},
+		{
+			name:    "finder character classes",
+			profile: ProfileFinder,
+			// ICU root collation: "_" < "-" < "," < "(" < "+" < "$"
+			ordered: []string{" a", "_a", "-a", ",a", "(a", "+a", "$a", "1a", "a"},
+		},
+		{
+			name:    "finder case and diacritics",
+			profile: ProfileFinder,
+			ordered: []string{"resume", "Resume", "résumé", "Résumé", "resumes"},
+		},
+		{
+			name:    "finder decomposed diacritics",
+			profile: ProfileFinder,
+			ordered: []string{"resume", "Resume", "re\u0301sume\u0301", "résumé", "Re\u0301sume\u0301", "Résumé", "resumes", "rf"},
+		},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			prev, ok := c.EncodeMixedTextProfile(i.ordered[0], i.profile)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.ordered[0])
+			}
+			for j := 1; j < len(i.ordered); j++ {
+				encoded, ok := c.EncodeMixedTextProfile(i.ordered[j], i.profile)
+				if !ok {
+					t.Fatalf("encoding failed for %q", i.ordered[j])
+				}
+				if prev >= encoded {
+					t.Fatalf("%q does not sort before %q", i.ordered[j-1], i.ordered[j])
+				}
+				prev = encoded
+			}
+		})
+	}
+}
+
+func TestEncodeMixedTextProfile_Default(t *testing.T) {
+	c := new(Codec)
+	input := "SomeCam350d"
+	expected, _ := c.EncodeMixedText(input)
+	out, ok := c.EncodeMixedTextProfile(input, ProfileDefault)
+	if !ok || out != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+
+	if _, ok := c.EncodeMixedTextProfile(input, MixedTextProfile(-1)); ok {
+		t.Fatal("unknown profile should fail")
+	}
+}
+
+func randomString(alphabet []byte, maxLength int) string {
+	b := make([]byte, rand.Intn(maxLength+1))
+	for i := range b {
+		b[i] = alphabet[rand.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareStrings(a string, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
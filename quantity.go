@@ -0,0 +1,190 @@
+package conust
+
+import (
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// quantityUnit is a unit that QuantityRecognizer knows, with the base unit it is normalized to.
+type quantityUnit struct {
+	base   string
+	factor int64
+}
+
+var quantityUnits = map[string]quantityUnit{
+	"B":   {"B", 1},
+	"bit": {"bit", 1},
+	"bps": {"bps", 1},
+	"s":   {"s", 1},
+	"min": {"s", 60},
+	"h":   {"s", 3600},
+	"Hz":  {"Hz", 1},
+	"m":   {"m", 1},
+	"g":   {"g", 1},
+	"L":   {"L", 1},
+	"V":   {"V", 1},
+	"A":   {"A", 1},
+	"W":   {"W", 1},
+	"Wh":  {"Wh", 1},
+	"J":   {"J", 1},
+	"N":   {"N", 1},
+	"Pa":  {"Pa", 1},
+	"F":   {"F", 1},
+	"Ω":   {"ohm", 1},
+	"ohm": {"ohm", 1},
+}
+
+// decimalPrefixes maps SI prefixes to powers of ten. The multiplying ones can also stand without a unit,
+// like in "10k".
+var decimalPrefixes = map[string]int{
+	"k": 3, "K": 3, "M": 6, "G": 9, "T": 12, "P": 15, "E": 18,
+	"m": -3, "u": -6, "µ": -6, "μ": -6, "n": -9, "p": -12,
+}
+
+// binaryPrefixes maps IEC prefixes to powers of 1024. They are only accepted with bytes and bits.
+var binaryPrefixes = map[string]uint{
+	"Ki": 1, "Mi": 2, "Gi": 3, "Ti": 4, "Pi": 5, "Ei": 6,
+}
+
+// QuantityRecognizer recognizes decimal numbers followed by a unit in mixed text, like "500ms", "1.5 s",
+// "900MB", "2GiB", "10k" or "3.3kΩ". It normalizes the value to the base unit, and encodes it as the
+// Conust token of the value followed by a space and the name of the base unit. So "900MB" sorts before
+// "1.5GB", and "500ms" before "1.5s".
+//
+// Units are case sensitive, SI prefixes are accepted with every unit, and IEC prefixes (Ki, Mi, ...)
+// with bytes and bits. A multiplying SI prefix can also stand alone, but only right after the number.
+// Numbers followed by anything else are left to the normal processing of mixed text.
+type QuantityRecognizer struct {
+	// BinaryBytes makes the decimal prefixes of bytes mean powers of 1024, so that 1KB is 1024B.
+	BinaryBytes bool
+}
+
+// Recognize implements the Recognizer interface.
+func (r QuantityRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	if !isDecimalDigit(input[pos]) || (pos > 0 && (isWordByte(input[pos-1]) || input[pos-1] == decimalPoint)) {
+		return 0, "", false
+	}
+
+	numberEnd := pos
+	for numberEnd < len(input) && isDecimalDigit(input[numberEnd]) {
+		numberEnd++
+	}
+	if numberEnd+1 < len(input) && input[numberEnd] == decimalPoint && isDecimalDigit(input[numberEnd+1]) {
+		numberEnd++
+		for numberEnd < len(input) && isDecimalDigit(input[numberEnd]) {
+			numberEnd++
+		}
+	}
+
+	unitStart := numberEnd
+	spaced := unitStart < len(input) && input[unitStart] == ' '
+	if spaced {
+		unitStart++
+	}
+	unitEnd := unitStart
+	for unitEnd < len(input) {
+		ch, size := utf8.DecodeRuneInString(input[unitEnd:])
+		if !unicode.IsLetter(ch) {
+			break
+		}
+		unitEnd += size
+	}
+	if unitEnd == unitStart || (unitEnd < len(input) && isWordByte(input[unitEnd])) {
+		return 0, "", false
+	}
+
+	base, factor, ok := r.resolveUnit(input[unitStart:unitEnd], !spaced)
+	if !ok {
+		return 0, "", false
+	}
+
+	value, ok := new(big.Rat).SetString(input[pos:numberEnd])
+	if !ok {
+		return 0, "", false
+	}
+	value.Mul(value, factor)
+	token, ok = c.EncodeToken(formatRat(value))
+	if !ok {
+		return 0, "", false
+	}
+	if base != "" {
+		token += string(inTextSeparator) + base
+	}
+	return unitEnd - pos, token, true
+}
+
+// resolveUnit finds the base unit and the multiplier of a unit with an optional prefix.
+func (r QuantityRecognizer) resolveUnit(unit string, allowBarePrefix bool) (base string, factor *big.Rat, ok bool) {
+	if known, ok := quantityUnits[unit]; ok {
+		return known.base, new(big.Rat).SetInt64(known.factor), true
+	}
+
+	for prefix, power := range binaryPrefixes {
+		if !strings.HasPrefix(unit, prefix) {
+			continue
+		}
+		if known, ok := quantityUnits[unit[len(prefix):]]; ok && (known.base == "B" || known.base == "bit") {
+			return known.base, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(known.factor), 10*power)), true
+		}
+	}
+
+	_, size := utf8.DecodeRuneInString(unit)
+	prefix := unit[:size]
+	power, isPrefix := decimalPrefixes[prefix]
+	if !isPrefix {
+		return "", nil, false
+	}
+	if size == len(unit) {
+		if !allowBarePrefix || power < 0 {
+			return "", nil, false
+		}
+		return "", powerOfTen(power), true
+	}
+
+	known, ok := quantityUnits[unit[size:]]
+	if !ok {
+		return "", nil, false
+	}
+	factor = powerOfTen(power)
+	if r.BinaryBytes && known.base == "B" && power > 0 {
+		factor = new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), 10*uint(power/3)))
+	}
+	return known.base, factor.Mul(factor, new(big.Rat).SetInt64(known.factor)), true
+}
+
+func powerOfTen(power int) *big.Rat {
+	p := power
+	if p < 0 {
+		p = -p
+	}
+	value := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(p)), nil)
+	if power < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), value)
+	}
+	return new(big.Rat).SetInt(value)
+}
+
+// formatRat writes a non negative rational number with a finite decimal expansion as a decimal number.
+func formatRat(value *big.Rat) string {
+	denominator := new(big.Int).Set(value.Denom())
+	remainder := new(big.Int)
+	decimals := 0
+	for _, factor := range []int64{2, 5} {
+		divisor := big.NewInt(factor)
+		count := 0
+		for {
+			quotient, _ := new(big.Int).QuoRem(denominator, divisor, remainder)
+			if remainder.Sign() != 0 {
+				break
+			}
+			denominator = quotient
+			count++
+		}
+		if count > decimals {
+			decimals = count
+		}
+	}
+	return value.FloatString(decimals)
+}
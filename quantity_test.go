@@ -0,0 +1,68 @@
+package conust
+
+import (
+	"testing"
+)
+
+func TestQuantityRecognizer(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		binaryBytes bool
+		output      string
+	}{
+		{name: "milliseconds", input: "500ms", output: "6z5 s"},
+		{name: "seconds", input: "1.5s", output: "7115 s"},
+		{name: "spaced", input: "took 1.5 s total", output: "took 7115 s total"},
+		{name: "minutes", input: "2min", output: "7312 s"},
+		{name: "megabytes", input: "900MB", output: "799 B"},
+		{name: "binary prefix", input: "2GiB", output: "7a2147483648 B"},
+		{name: "binary bytes", input: "1KB", binaryBytes: true, output: "741024 B"},
+		{name: "bare prefix", input: "10k", output: "751"},
+		{name: "ohms", input: "3.3kΩ", output: "7433 ohm"},
+		{name: "micro", input: "47µF", output: "6v47 F"},
+		{name: "unknown unit", input: "12cats", output: "7212 cats"},
+		{name: "spaced bare prefix", input: "10 k", output: "721 k"},
+		{name: "unit inside a word", input: "5ms2", output: "715 ms 712"},
+		{name: "binary prefix with other unit", input: "2Gis", output: "712 Gis"},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			c := &Codec{Recognizers: []Recognizer{QuantityRecognizer{BinaryBytes: i.binaryBytes}}}
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.output {
+				t.Fatalf("output expected %q got %q", i.output, encoded)
+			}
+		})
+	}
+}
+
+func TestQuantityRecognizer_Order(t *testing.T) {
+	ordered := []string{
+		"disk 512KB",
+		"disk 900MB",
+		"disk 1GiB",
+		"disk 1.5GB",
+		"disk 2TB",
+		"latency 900us",
+		"latency 1ms",
+		"latency 500ms",
+		"latency 1.5s",
+		"latency 2min",
+		"latency 1h",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{QuantityRecognizer{}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
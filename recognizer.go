@@ -0,0 +1,34 @@
+package conust
+
+// Recognizer finds values in mixed text that should be encoded as a whole instead of as separate groups
+// of decimal digits.
+type Recognizer interface {
+	// Recognize checks whether a value starts at position pos of the input. If it does, it returns the
+	// length of the value and the token to write in its place. The token is surrounded by in text
+	// separators, so it must sort correctly when followed by a space.
+	Recognize(c *Codec, input string, pos int) (length int, token string, ok bool)
+}
+
+// matchToken returns the end of the value starting at pos together with its encoded version, or pos itself
+// if there is no value there. Recognizers take precedence over groups of decimal digits.
+func (c *Codec) matchToken(input string, pos int) (end int, encoded string, ok bool) {
+	for _, recognizer := range c.Recognizers {
+		if length, token, found := recognizer.Recognize(c, input, pos); found && length > 0 {
+			return pos + length, token, true
+		}
+	}
+
+	if !isDecimalDigit(input[pos]) {
+		return pos, "", false
+	}
+	end = pos + 1
+	for end < len(input) && isDecimalDigit(input[end]) {
+		end++
+	}
+	encoded, ok = c.EncodeToken(input[pos:end])
+	return
+}
+
+func isWordByte(ch byte) bool {
+	return isASCIILetter(ch) || isDecimalDigit(ch) || ch == '_'
+}
@@ -0,0 +1,169 @@
+package conust
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultRomanExclusions are the words RomanRecognizer skips when its Exclude field is nil: valid
+// numerals that are much more likely to be words or abbreviations in ordinary text.
+var DefaultRomanExclusions = []string{"mix", "mi", "di", "cv", "cm", "cl", "mc"}
+
+// DefaultRomanTitleWords are the words after which RomanRecognizer recognizes single letter numerals
+// when its TitleWords field is nil.
+var DefaultRomanTitleWords = []string{
+	"part", "chapter", "chap", "book", "vol", "volume", "act", "scene", "episode", "season", "phase", "stage",
+}
+
+var romanSymbols = [...]struct {
+	symbol string
+	value  int
+}{
+	{"m", 1000}, {"cm", 900}, {"d", 500}, {"cd", 400},
+	{"c", 100}, {"xc", 90}, {"l", 50}, {"xl", 40},
+	{"x", 10}, {"ix", 9}, {"v", 5}, {"iv", 4}, {"i", 1},
+}
+
+const maxRomanValue = 3999
+
+// RomanRecognizer recognizes standalone Roman numerals in mixed text, like "II", "IV" or "xii", and
+// encodes them as the Conust token of their value, so "Part IX" sorts before "Part X".
+//
+// Only canonical numerals between 1 and 3999 written either all upper case or all lower case are
+// recognized, and they must be whole words. Single letters are more often pronouns, variables, units or
+// names, like in "I saw", "2 x 4", "5 m" or "vitamin C", so they are only recognized after one of the
+// TitleWords, like "Part V" or "Vol. X". An upper case letter other than "I" is also recognized when it
+// ends the input after a capitalized word, like "Rocky V". The words listed in Exclude are skipped.
+type RomanRecognizer struct {
+	// AllowI makes every standalone "I" and "i" recognized as the number one.
+	AllowI bool
+	// AllowSingleLetters makes every standalone "V", "X", "L", "C", "D" and "M", in either case, recognized.
+	AllowSingleLetters bool
+	// TitleWords lists words (compared case insensitively) after which single letter numerals are
+	// recognized, optionally followed by a dot. DefaultRomanTitleWords is used if it is nil.
+	TitleWords []string
+	// Exclude lists words (compared case insensitively) that are never recognized.
+	// DefaultRomanExclusions is used if it is nil.
+	Exclude []string
+}
+
+// Recognize implements the Recognizer interface.
+func (r RomanRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	if pos > 0 && isWordByte(input[pos-1]) {
+		return 0, "", false
+	}
+
+	end := pos
+	for end < len(input) && isASCIILetter(input[end]) {
+		end++
+	}
+	if end == pos || (end < len(input) && isWordByte(input[end])) {
+		return 0, "", false
+	}
+
+	word := input[pos:end]
+	lower := strings.ToLower(word)
+	if word != lower && word != strings.ToUpper(word) {
+		return 0, "", false
+	}
+	if len(word) == 1 && !r.allowSingleLetter(input, pos, end) {
+		return 0, "", false
+	}
+	exclude := r.Exclude
+	if exclude == nil {
+		exclude = DefaultRomanExclusions
+	}
+	for _, excluded := range exclude {
+		if strings.EqualFold(lower, excluded) {
+			return 0, "", false
+		}
+	}
+
+	value, ok := parseRoman(lower)
+	if !ok {
+		return 0, "", false
+	}
+	token, ok = c.EncodeToken(strconv.Itoa(value))
+	return end - pos, token, ok
+}
+
+// allowSingleLetter tells whether the single letter numeral between pos and end is recognized.
+func (r RomanRecognizer) allowSingleLetter(input string, pos int, end int) bool {
+	if input[pos] == 'i' || input[pos] == 'I' {
+		if r.AllowI {
+			return true
+		}
+	} else {
+		if r.AllowSingleLetters {
+			return true
+		}
+		// the end of a title, like "Rocky V"
+		if input[pos] <= 'Z' && end == len(input) && isCapitalized(previousWord(input, pos, false)) {
+			return true
+		}
+	}
+
+	titleWords := r.TitleWords
+	if titleWords == nil {
+		titleWords = DefaultRomanTitleWords
+	}
+	previous := previousWord(input, pos, true)
+	for _, titleWord := range titleWords {
+		if strings.EqualFold(previous, titleWord) {
+			return true
+		}
+	}
+	return false
+}
+
+// previousWord returns the word of ASCII letters that precedes pos, separated from it by spaces, and if
+// allowDot is set, by a dot right after the word. It returns "" if there is no such word.
+func previousWord(input string, pos int, allowDot bool) string {
+	end := pos
+	for end > 0 && input[end-1] == ' ' {
+		end--
+	}
+	if end == pos {
+		return ""
+	}
+	if allowDot && end > 0 && input[end-1] == decimalPoint {
+		end--
+	}
+	start := end
+	for start > 0 && isASCIILetter(input[start-1]) {
+		start--
+	}
+	if start > 0 && isWordByte(input[start-1]) {
+		return ""
+	}
+	return input[start:end]
+}
+
+func isCapitalized(word string) bool {
+	return word != "" && word[0] >= 'A' && word[0] <= 'Z'
+}
+
+// parseRoman parses a lower case Roman numeral, accepting only its canonical form.
+func parseRoman(numeral string) (value int, ok bool) {
+	rest := numeral
+	for _, symbol := range romanSymbols {
+		for strings.HasPrefix(rest, symbol.symbol) {
+			value += symbol.value
+			rest = rest[len(symbol.symbol):]
+		}
+	}
+	if rest != "" || value == 0 || value > maxRomanValue || formatRoman(value) != numeral {
+		return 0, false
+	}
+	return value, true
+}
+
+func formatRoman(value int) string {
+	var b strings.Builder
+	for _, symbol := range romanSymbols {
+		for ; value >= symbol.value; value -= symbol.value {
+			b.WriteString(symbol.symbol)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package conust
+
+import (
+	"testing"
+)
+
+func TestParseRoman(t *testing.T) {
+	testCases := []struct {
+		numeral string
+		value   int
+		ok      bool
+	}{
+		{"i", 1, true},
+		{"iv", 4, true},
+		{"ix", 9, true},
+		{"xii", 12, true},
+		{"xliv", 44, true},
+		{"mcmxcix", 1999, true},
+		{"mmmcmxcix", 3999, true},
+		{"iiii", 0, false},
+		{"vx", 0, false},
+		{"ic", 0, false},
+		{"mmmm", 0, false},
+		{"did", 0, false},
+		{"civic", 0, false},
+		{"", 0, false},
+	}
+
+	for _, i := range testCases {
+		value, ok := parseRoman(i.numeral)
+		if ok != i.ok || value != i.value {
+			t.Fatalf("%q expected %d, %v got %d, %v", i.numeral, i.value, i.ok, value, ok)
+		}
+	}
+}
+
+func TestRomanRecognizer(t *testing.T) {
+	testCases := []struct {
+		name       string
+		recognizer RomanRecognizer
+		input      string
+		output     string
+	}{
+		{name: "title", input: "Rocky IV", output: "Rocky 714"},
+		{name: "lower case", input: "chapter xii.", output: "chapter 7212 ."},
+		{name: "pronoun", input: "I saw World War II", output: "I saw World War 712"},
+		{name: "allow I", recognizer: RomanRecognizer{AllowI: true}, input: "Part I", output: "Part 711"},
+		{name: "mixed case", input: "Vi and Mix", output: "Vi and Mix"},
+		{name: "excluded", input: "5 cm MIX", output: "715 cm MIX"},
+		{name: "not excluded", input: "vi xl lx cc mm cd dc", output: "716 724 726 732 742 734 736"},
+		{name: "custom exclusions", recognizer: RomanRecognizer{Exclude: []string{}}, input: "MIX", output: "741009"},
+		{name: "inside a word", input: "XIIth VIPs", output: "XIIth VIPs"},
+		{name: "not canonical", input: "IIII", output: "IIII"},
+		{name: "times", input: "2 x 4", output: "712 x 714"},
+		{name: "unit", input: "5 m", output: "715 m"},
+		{name: "vitamin", input: "vitamin C", output: "vitamin C"},
+		{name: "single letters", input: "Part V, see appendix D", output: "Part 715 , see appendix D"},
+		{name: "title words", input: "Vol. X chapter i", output: "Vol. 721 chapter 711"},
+		{name: "custom title words", recognizer: RomanRecognizer{TitleWords: []string{"appendix"}}, input: "Part V, see appendix D", output: "Part V, see appendix 735"},
+		{name: "title end", input: "Rocky V", output: "Rocky 715"},
+		{name: "lower case title end", input: "Rocky v", output: "Rocky v"},
+		{name: "not a title end", input: "Rocky V 2", output: "Rocky V 712"},
+		{name: "pronoun at the end", input: "Did I", output: "Did I"},
+		{name: "allow single letters", recognizer: RomanRecognizer{AllowSingleLetters: true}, input: "Part V, Appendix D", output: "Part 715 , Appendix 735"},
+		{name: "allow single letters but I", recognizer: RomanRecognizer{AllowSingleLetters: true}, input: "I x", output: "I 721"},
+	}
+
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			c := &Codec{Recognizers: []Recognizer{i.recognizer}}
+			encoded, ok := c.EncodeMixedText(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.output {
+				t.Fatalf("output expected %q got %q", i.output, encoded)
+			}
+		})
+	}
+}
+
+func TestRomanRecognizer_Order(t *testing.T) {
+	ordered := []string{
+		"Part I",
+		"Part II",
+		"Part III",
+		"Part IV",
+		"Part V",
+		"Part IX",
+		"Part X",
+		"Part XI",
+		"Part XL",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{RomanRecognizer{AllowI: true, AllowSingleLetters: true}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
+
+func TestRomanRecognizer_DefaultOrder(t *testing.T) {
+	ordered := []string{
+		"Part I",
+		"Part II",
+		"Part IV",
+		"Part V",
+		"Part VI",
+		"Part IX",
+		"Part X",
+		"Part XI",
+		"Part XL",
+		"Part L",
+		"Part LX",
+		"Rocky",
+		"Rocky II",
+		"Rocky V",
+	}
+
+	c := &Codec{Recognizers: []Recognizer{RomanRecognizer{}}}
+	prev, _ := c.EncodeMixedText(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeMixedText(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
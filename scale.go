@@ -0,0 +1,71 @@
+package conust
+
+import (
+	"strconv"
+	"strings"
+)
+
+// scaleSeparator starts the scale suffix of EncodeTokenScale. It sorts before all digits, so a token
+// with a suffix still sorts before the longer tokens it is the prefix of.
+const scaleSeparator byte = '.'
+
+// EncodeTokenScale turns the input number into a token like EncodeToken does, but keeps the scale of the
+// input, the number of its fractional digits, so that DecodeToken gives back "1.50" rather than "1.5".
+// The scale is appended to the token as a separator and the token of the scale, unless it is zero, so the
+// tokens of integers are the same as the ones of EncodeToken. The outputs sort by value, and equal values
+// by their scale, like "1" < "1.0" < "1.5" < "1.50".
+func (c *Codec) EncodeTokenScale(input string) (out string, ok bool) {
+	token, ok := c.EncodeToken(input)
+	if !ok || token == "" {
+		return token, ok
+	}
+
+	scale := 0
+	if pos := strings.IndexByte(input, decimalPoint); pos >= 0 {
+		scale = len(input) - pos - 1
+	}
+	if scale == 0 {
+		return token, true
+	}
+	scaleToken, _ := c.EncodeToken(strconv.Itoa(scale))
+	return token + string(scaleSeparator) + scaleToken, true
+}
+
+// decodeScaledToken decodes a token of EncodeTokenScale, padding the fractional part of the number with
+// zeros to the scale.
+func (c *Codec) decodeScaledToken(token string, scaleToken string) (out string, ok bool) {
+	scaleText, ok := c.DecodeToken(scaleToken)
+	if !ok {
+		return "", false
+	}
+	scale, err := strconv.Atoi(scaleText)
+	if err != nil || scale <= 0 {
+		return "", false
+	}
+	if canonical, _ := c.EncodeToken(scaleText); canonical != scaleToken {
+		return "", false
+	}
+
+	number, ok := c.DecodeToken(token)
+	if !ok || number == "" {
+		return "", false
+	}
+	fractionLength := 0
+	if pos := strings.IndexByte(number, decimalPoint); pos >= 0 {
+		fractionLength = len(number) - pos - 1
+	}
+	if fractionLength > scale {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.Grow(len(number) + scale - fractionLength + 1)
+	b.WriteString(number)
+	if fractionLength == 0 {
+		b.WriteByte(decimalPoint)
+	}
+	for i := fractionLength; i < scale; i++ {
+		b.WriteByte(digit0)
+	}
+	return b.String(), true
+}
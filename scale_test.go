@@ -0,0 +1,92 @@
+package conust
+
+import (
+	"math/big"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncodeTokenScale(t *testing.T) {
+	testCases := []struct {
+		input   string
+		encoded string
+		decoded string
+	}{
+		{input: "", encoded: "", decoded: ""},
+		{input: "12", encoded: "7212", decoded: "12"},
+		{input: "1.50", encoded: "7115.712", decoded: "1.50"},
+		{input: "+1.5", encoded: "7115.711", decoded: "1.5"},
+		{input: "1.0", encoded: "711.711", decoded: "1.0"},
+		{input: "0.00", encoded: "5.712", decoded: "0.00"},
+		{input: "-0.050", encoded: "41u~.713", decoded: "-0.050"},
+		{input: "120.000000000000", encoded: "7312.7212", decoded: "120.000000000000"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.input, func(t *testing.T) {
+			encoded, ok := c.EncodeTokenScale(i.input)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeToken(encoded)
+			if !ok || decoded != i.decoded {
+				t.Fatalf("decoding expected %q got %q", i.decoded, decoded)
+			}
+		})
+	}
+
+	for _, input := range []string{"7115.", "7115.5", "7115.3yy~", "7115.7115", "7115.711.711", ".711", "7115.7120", "71150.711"} {
+		if _, ok := c.DecodeToken(input); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestEncodeTokenScale_Order(t *testing.T) {
+	c := new(Codec)
+	ordered := []string{"-1.5", "-1.50", "-1", "0", "0.0", "0.00", "1", "1.0", "1.00", "1.5", "1.50", "1.500", "1.51"}
+	prev, _ := c.EncodeTokenScale(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, _ := c.EncodeTokenScale(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+
+	rand.Seed(42)
+	numbers := make([]string, 2000)
+	for i := range numbers {
+		numbers[i] = strconv.FormatFloat(rand.NormFloat64()*100, 'f', rand.Intn(12), 64)
+		if value, _ := new(big.Rat).SetString(numbers[i]); value.Sign() == 0 {
+			numbers[i] = strings.TrimPrefix(numbers[i], "-")
+		}
+	}
+	keys := make([]string, len(numbers))
+	for i, number := range numbers {
+		keys[i], _ = c.EncodeTokenScale(number)
+	}
+	sort.Strings(keys)
+	for i := 1; i < len(keys); i++ {
+		a, _ := c.DecodeToken(keys[i-1])
+		b, _ := c.DecodeToken(keys[i])
+		ratA, _ := new(big.Rat).SetString(a)
+		ratB, _ := new(big.Rat).SetString(b)
+		if ratA.Cmp(ratB) > 0 {
+			t.Fatalf("%s sorts before %s", a, b)
+		}
+	}
+	for i, number := range numbers {
+		encoded, _ := c.EncodeTokenScale(number)
+		if decoded, ok := c.DecodeToken(encoded); !ok || decoded != number {
+			t.Fatalf("decoding %q expected %q got %q", encoded, numbers[i], decoded)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package conust
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const nanosPerSecond = 1000000000
+const nanosDigits = 9
+
+// EncodeTime turns a point in time into the Conust token of the number of seconds elapsed since the
+// Unix epoch, with nanosecond precision. Being a plain Conust number, it sorts correctly for any year
+// time.Time can represent, including years before 0001 and after 9999, and it can share a key space
+// with other Conust numbers. The time zone is dropped, EncodeTimeWithOffset keeps it.
+func (c *Codec) EncodeTime(t time.Time) string {
+	out, _ := c.EncodeToken(formatSeconds(t.Unix(), int64(t.Nanosecond())))
+	return out
+}
+
+// EncodeTimeWithOffset works like EncodeTime, but appends the UTC offset of the time zone of t in seconds
+// as a second token, separated by a space. Equal instants are then ordered by their offset.
+func (c *Codec) EncodeTimeWithOffset(t time.Time) string {
+	_, offset := t.Zone()
+	offsetToken, _ := c.EncodeToken(strconv.Itoa(offset))
+	return c.EncodeTime(t) + string(inTextSeparator) + offsetToken
+}
+
+// DecodeTime turns a string generated by EncodeTime or EncodeTimeWithOffset back into a time.
+// The time is in UTC, or in a fixed zone with the original offset if the offset was encoded.
+func (c *Codec) DecodeTime(input string) (t time.Time, ok bool) {
+	zone := time.UTC
+	if separatorPos := strings.IndexByte(input, inTextSeparator); separatorPos >= 0 {
+		offsetValue, ok := c.DecodeToken(input[separatorPos+1:])
+		if !ok {
+			return time.Time{}, false
+		}
+		offset, err := strconv.Atoi(offsetValue)
+		if err != nil {
+			return time.Time{}, false
+		}
+		zone = time.FixedZone("", offset)
+		input = input[:separatorPos]
+	}
+
+	seconds, nanos, ok := c.decodeSeconds(input)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, nanos).In(zone), true
+}
+
+// EncodeDuration turns a duration into the Conust token of its length in seconds, with nanosecond
+// precision. Negative durations sort before positive ones.
+func (c *Codec) EncodeDuration(d time.Duration) string {
+	nanos := int64(d)
+	out, _ := c.EncodeToken(formatSeconds(nanos/nanosPerSecond, nanos%nanosPerSecond))
+	return out
+}
+
+// DecodeDuration turns a string generated by EncodeDuration back into a duration.
+func (c *Codec) DecodeDuration(input string) (d time.Duration, ok bool) {
+	seconds, nanos, ok := c.decodeSeconds(input)
+	if !ok {
+		return 0, false
+	}
+	if seconds > (1<<63-1)/nanosPerSecond || seconds < -(1<<63)/nanosPerSecond {
+		return 0, false
+	}
+	total := seconds*nanosPerSecond + nanos
+	if (seconds > 0 && total < 0) || (seconds < 0 && total > 0) {
+		return 0, false
+	}
+	return time.Duration(total), true
+}
+
+// formatSeconds writes a number of seconds and nanoseconds as a decimal number. The two values must
+// have the same sign (as with durations) or the nanoseconds must be non negative (as with time.Unix).
+func formatSeconds(seconds int64, nanos int64) string {
+	negative := seconds < 0 || nanos < 0
+	if negative && nanos > 0 {
+		seconds++
+		nanos = nanosPerSecond - nanos
+	}
+	if seconds < 0 {
+		seconds = -seconds
+	}
+	if nanos < 0 {
+		nanos = -nanos
+	}
+
+	fraction := strconv.FormatInt(nanos, 10)
+	var b strings.Builder
+	b.Grow(32)
+	if negative {
+		b.WriteByte(minusByte)
+	}
+	b.WriteString(strconv.FormatUint(uint64(seconds), 10))
+	b.WriteByte(decimalPoint)
+	b.WriteString(strings.Repeat(zeroInput, nanosDigits-len(fraction)))
+	b.WriteString(fraction)
+	return b.String()
+}
+
+// decodeSeconds decodes a token into whole seconds and nanoseconds of the same sign.
+func (c *Codec) decodeSeconds(input string) (seconds int64, nanos int64, ok bool) {
+	value, ok := c.DecodeToken(input)
+	if !ok || value == "" {
+		return 0, 0, false
+	}
+
+	negative := value[0] == minusByte
+	if negative {
+		value = value[1:]
+	}
+	integer, fraction := value, ""
+	if pointPos := strings.IndexByte(value, decimalPoint); pointPos >= 0 {
+		integer, fraction = value[:pointPos], value[pointPos+1:]
+	}
+	if len(fraction) > nanosDigits {
+		return 0, 0, false
+	}
+
+	seconds, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if fraction != "" {
+		nanos, err = strconv.ParseInt(fraction+strings.Repeat(zeroInput, nanosDigits-len(fraction)), 10, 64)
+		if err != nil || nanos < 0 {
+			return 0, 0, false
+		}
+	}
+	if negative {
+		return -seconds, -nanos, true
+	}
+	return seconds, nanos, true
+}
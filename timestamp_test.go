@@ -0,0 +1,142 @@
+package conust
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncodeTime(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   time.Time
+		encoded string
+	}{
+		{name: "epoch", input: time.Unix(0, 0), encoded: "5"},
+		{name: "one day", input: time.Unix(86400, 0), encoded: "75864"},
+		{name: "nanosecond", input: time.Unix(0, 1), encoded: "6r1"},
+		{name: "before epoch", input: time.Unix(-1, 500000000), encoded: "40u~"},
+		{name: "time zone", input: time.Date(1970, 1, 1, 2, 0, 0, 0, time.FixedZone("", 7200)), encoded: "5"},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			encoded := c.EncodeTime(i.input)
+			if encoded != i.encoded {
+				t.Fatalf("encoding expected %q got %q", i.encoded, encoded)
+			}
+			decoded, ok := c.DecodeTime(encoded)
+			if !ok {
+				t.Fatalf("decoding failed for %q", encoded)
+			}
+			if !decoded.Equal(i.input) || decoded.Location() != time.UTC {
+				t.Fatalf("decoding expected %v got %v", i.input.UTC(), decoded)
+			}
+		})
+	}
+}
+
+func TestEncodeTime_Order(t *testing.T) {
+	ordered := []time.Time{
+		time.Date(-50000, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(-1, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 1, time.UTC),
+		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1970, 1, 1, 0, 0, 0, 1, time.UTC),
+		time.Date(2024, 1, 5, 10, 0, 0, 0, time.FixedZone("", 3600)),
+		time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(123456, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	c := new(Codec)
+	prev := c.EncodeTime(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded := c.EncodeTime(ordered[i])
+		if prev >= encoded {
+			t.Fatalf("%v does not sort before %v", ordered[i-1], ordered[i])
+		}
+		decoded, ok := c.DecodeTime(encoded)
+		if !ok || !decoded.Equal(ordered[i]) {
+			t.Fatalf("decoding expected %v got %v", ordered[i], decoded)
+		}
+		prev = encoded
+	}
+}
+
+func TestEncodeTimeWithOffset(t *testing.T) {
+	c := new(Codec)
+	instant := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+	ordered := []time.Time{
+		instant.In(time.FixedZone("", -5*3600)),
+		instant,
+		instant.In(time.FixedZone("", 3600)),
+		instant.Add(time.Nanosecond).In(time.FixedZone("", -5*3600)),
+	}
+
+	prev := ""
+	for _, i := range ordered {
+		encoded := c.EncodeTimeWithOffset(i)
+		if prev >= encoded {
+			t.Fatalf("%v does not sort after %q", i, prev)
+		}
+		decoded, ok := c.DecodeTime(encoded)
+		if !ok {
+			t.Fatalf("decoding failed for %q", encoded)
+		}
+		if !decoded.Equal(i) || decoded.Format(time.RFC3339Nano) != i.Format(time.RFC3339Nano) {
+			t.Fatalf("decoding expected %v got %v", i, decoded)
+		}
+		prev = encoded
+	}
+}
+
+func TestDecodeTime_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []string{"", "X", "71a", "6w0000000001", "5 X", "5 72a", "7z4123"} {
+		if _, ok := c.DecodeTime(input); ok {
+			t.Fatalf("decoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestEncodeDuration(t *testing.T) {
+	ordered := []time.Duration{
+		time.Duration(-1 << 63),
+		-time.Hour,
+		-1500 * time.Millisecond,
+		-time.Second,
+		-time.Nanosecond,
+		0,
+		time.Nanosecond,
+		500 * time.Millisecond,
+		time.Second,
+		90 * time.Minute,
+		time.Duration(1<<63 - 1),
+	}
+
+	c := new(Codec)
+	prev := LessThanAny
+	for _, i := range ordered {
+		encoded := c.EncodeDuration(i)
+		if prev >= encoded {
+			t.Fatalf("%v does not sort after %q", i, prev)
+		}
+		decoded, ok := c.DecodeDuration(encoded)
+		if !ok || decoded != i {
+			t.Fatalf("decoding expected %v got %v", i, decoded)
+		}
+		prev = encoded
+	}
+
+	if encoded := c.EncodeDuration(1500 * time.Millisecond); encoded != "7115" {
+		t.Fatalf("encoding expected %q got %q", "7115", encoded)
+	}
+	if _, ok := c.DecodeDuration(c.EncodeTime(time.Date(2400, 1, 1, 0, 0, 0, 0, time.UTC))); ok {
+		t.Fatal("decoding an out of range duration should fail")
+	}
+}
@@ -0,0 +1,86 @@
+package conust
+
+// EncodeTokenMax works like EncodeToken, but if the token would be longer than maxBytes, it drops
+// significant digits from its end, which rounds the number toward zero. The truncated tokens keep the
+// order of the numbers, although different numbers may end up with the same token, which is reported by
+// truncated. Encoding fails if maxBytes is too short to hold the sign, the magnitude and a single digit.
+func (c *Codec) EncodeTokenMax(input string, maxBytes int) (out string, truncated bool, ok bool) {
+	out, ok = c.EncodeToken(input)
+	if !ok || len(out) <= maxBytes {
+		return out, false, ok
+	}
+	out, ok = c.truncateToken(out, maxBytes)
+	return out, ok, ok
+}
+
+// EncodeMixedTextMax works like EncodeMixedText, but limits the output to maxBytes, for example to fit in
+// the index of a database. The outputs keep the order of the inputs, but different inputs may end up with
+// the same output. Whenever the output was shortened, truncated is true, so the caller knows to compare
+// the original strings when the outputs are equal.
+//
+// The only cut that keeps the order of any two outputs is at the same byte position, so the output may
+// end with an incomplete UTF-8 character, which makes it better suited for binary columns. A token is cut
+// between its significant digits, rounding it toward zero like EncodeTokenMax, which keeps it valid.
+func (c *Codec) EncodeMixedTextMax(input string, maxBytes int) (out string, truncated bool, ok bool) {
+	cutStart, cutEnd := -1, -1
+	out, ok = c.encodeMixedText(input, func(start int, end int) {
+		if start < maxBytes && maxBytes < end {
+			cutStart, cutEnd = start, end
+		}
+	})
+	if len(out) <= maxBytes {
+		return out, false, ok
+	}
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+
+	// Negative tokens need their terminator, the others are valid when cut after their magnitude.
+	if cutStart >= 0 && !isPositiveToken(out[cutStart:cutEnd]) {
+		if _, decodeOk := c.DecodeToken(out[cutStart:cutEnd]); decodeOk {
+			if token, tokenOk := c.truncateToken(out[cutStart:cutEnd], maxBytes-cutStart); tokenOk {
+				return out[:cutStart] + token, true, ok
+			}
+		}
+	}
+	return out[:maxBytes], true, ok
+}
+
+func isPositiveToken(token string) bool {
+	return token[0] == signPositiveMagPositive || token[0] == signPositiveMagNegative || token == zeroOutput
+}
+
+// truncateToken shortens a valid token to at most maxBytes by dropping significant digits, and also the
+// zeros that would end up at the end of the remaining digits.
+func (c *Codec) truncateToken(token string, maxBytes int) (out string, ok bool) {
+	if token == zeroOutput || len(token) <= maxBytes {
+		return token, len(token) <= maxBytes
+	}
+
+	positive, magnitudePositive, ok := c.decodeSigns(token)
+	if !ok {
+		return "", false
+	}
+	_, significantPartPos, ok := c.decodeMagnitude(token, positive, magnitudePositive)
+	if !ok {
+		return "", false
+	}
+
+	end := maxBytes
+	zero := digit0
+	if !positive {
+		end--
+		zero = digitZ
+	}
+	if end <= significantPartPos {
+		return "", false
+	}
+	for token[end-1] == zero {
+		end--
+	}
+
+	if positive {
+		return token[:end], true
+	}
+	return token[:end] + string(negativeNumberTerminator), true
+}
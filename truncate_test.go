@@ -0,0 +1,146 @@
+package conust
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestEncodeTokenMax(t *testing.T) {
+	testCases := []struct {
+		input     string
+		maxBytes  int
+		output    string
+		truncated bool
+		ok        bool
+	}{
+		{input: "1234567", maxBytes: 9, output: "771234567", truncated: false, ok: true},
+		{input: "1234567", maxBytes: 4, output: "7712", truncated: true, ok: true},
+		{input: "1000001", maxBytes: 5, output: "771", truncated: true, ok: true},
+		{input: "0.000123", maxBytes: 4, output: "6w12", truncated: true, ok: true},
+		{input: "-1.55", maxBytes: 5, output: "3yyu~", truncated: true, ok: true},
+		{input: "-1.05", maxBytes: 5, output: "3yy~", truncated: true, ok: true},
+		{input: "0", maxBytes: 1, output: "5", truncated: false, ok: true},
+		{input: "1234567", maxBytes: 2, output: "", truncated: false, ok: false},
+		{input: "-1.55", maxBytes: 3, output: "", truncated: false, ok: false},
+	}
+
+	c := new(Codec)
+	for _, i := range testCases {
+		out, truncated, ok := c.EncodeTokenMax(i.input, i.maxBytes)
+		if out != i.output || truncated != i.truncated || ok != i.ok {
+			t.Fatalf("%q in %d bytes expected %q, %v, %v got %q, %v, %v",
+				i.input, i.maxBytes, i.output, i.truncated, i.ok, out, truncated, ok)
+		}
+	}
+}
+
+func TestEncodeTokenMax_Order(t *testing.T) {
+	c := new(Codec)
+	rand.Seed(42)
+	for i := 0; i < 50000; i++ {
+		a := rand.NormFloat64() * 1000
+		b := rand.NormFloat64() * 1000
+		if a > b {
+			a, b = b, a
+		}
+		maxBytes := 4 + rand.Intn(8)
+		encodedA, _, okA := c.EncodeTokenMax(strconv.FormatFloat(a, 'f', -1, 64), maxBytes)
+		encodedB, _, okB := c.EncodeTokenMax(strconv.FormatFloat(b, 'f', -1, 64), maxBytes)
+		if !okA || !okB {
+			t.Fatalf("encoding failed for %v or %v", a, b)
+		}
+		if encodedA > encodedB {
+			t.Fatalf("%v and %v in %d bytes give %q and %q", a, b, maxBytes, encodedA, encodedB)
+		}
+		if _, ok := c.DecodeToken(encodedA); !ok {
+			t.Fatalf("%q is not a valid token", encodedA)
+		}
+	}
+}
+
+func TestEncodeMixedTextMax(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		maxBytes  int
+		output    string
+		truncated bool
+	}{
+		{name: "short", input: "Item 12", maxBytes: 10, output: "Item 7212", truncated: false},
+		{name: "text", input: "Item 12 of the list", maxBytes: 12, output: "Item 7212 of", truncated: true},
+		{name: "utf8", input: "Café", maxBytes: 4, output: "Caf\xc3", truncated: true},
+		{name: "token digits", input: "Item 12345678 x", maxBytes: 8, output: "Item 781", truncated: true},
+		{name: "token header", input: "Item 12345678 x", maxBytes: 6, output: "Item 7", truncated: true},
+		{name: "negative token", input: "t -1.0995 x", maxBytes: 7, output: "t 3yy~", truncated: true},
+		{name: "zero", input: "", maxBytes: 0, output: "", truncated: false},
+	}
+
+	c := &Codec{Recognizers: []Recognizer{signedNumberRecognizer{}}}
+	for _, i := range testCases {
+		t.Run(i.name, func(t *testing.T) {
+			out, truncated, ok := c.EncodeMixedTextMax(i.input, i.maxBytes)
+			if !ok {
+				t.Fatalf("encoding failed for %q", i.input)
+			}
+			if out != i.output || truncated != i.truncated {
+				t.Fatalf("output expected %q, %v got %q, %v", i.output, i.truncated, out, truncated)
+			}
+		})
+	}
+}
+
+func TestEncodeMixedTextMax_Order(t *testing.T) {
+	alphabet := []byte{' ', '-', '-', '/', '.', '.', 'a', 'b', 0xc3, 0xa9, '0', '1', '5', '9', '9'}
+	codecs := []*Codec{
+		new(Codec),
+		{Recognizers: []Recognizer{FractionRecognizer{}, QuantityRecognizer{}}},
+		{Recognizers: []Recognizer{signedNumberRecognizer{}}},
+		{NumberPlacement: NumbersFirst},
+	}
+
+	rand.Seed(42)
+	for _, c := range codecs {
+		for i := 0; i < 50000; i++ {
+			a := randomString(alphabet, 16)
+			b := randomString(alphabet, 16)
+			encodedA, _ := c.EncodeMixedText(a)
+			encodedB, _ := c.EncodeMixedText(b)
+			if encodedA > encodedB {
+				a, b = b, a
+			}
+			maxBytes := rand.Intn(16)
+			limitedA, truncatedA, _ := c.EncodeMixedTextMax(a, maxBytes)
+			limitedB, _, _ := c.EncodeMixedTextMax(b, maxBytes)
+			if limitedA > limitedB {
+				t.Fatalf("%q and %q in %d bytes give %q and %q", a, b, maxBytes, limitedA, limitedB)
+			}
+			if len(limitedA) > maxBytes || truncatedA != (limitedA != mustEncodeMixedText(c, a)) {
+				t.Fatalf("%q in %d bytes gives %q, truncated %v", a, maxBytes, limitedA, truncatedA)
+			}
+		}
+	}
+}
+
+func mustEncodeMixedText(c *Codec, input string) string {
+	encoded, _ := c.EncodeMixedText(input)
+	return encoded
+}
+
+// signedNumberRecognizer recognizes signed decimal numbers, to have negative tokens in mixed text.
+type signedNumberRecognizer struct{}
+
+func (signedNumberRecognizer) Recognize(c *Codec, input string, pos int) (length int, token string, ok bool) {
+	end := pos
+	if input[end] == minusByte {
+		end++
+	}
+	for end < len(input) && (isDecimalDigit(input[end]) || input[end] == decimalPoint) {
+		end++
+	}
+	if end == pos || end-pos == 1 && input[pos] == minusByte {
+		return 0, "", false
+	}
+	token, ok = c.EncodeToken(input[pos:end])
+	return end - pos, token, ok
+}
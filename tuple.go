@@ -0,0 +1,215 @@
+package conust
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ElementKind tells the type of an element of a tuple key.
+type ElementKind int
+
+const (
+	// NullElement is an element without a value, it sorts before every number and string.
+	NullElement ElementKind = iota
+	// NumberElement is a number, sorted by value before every string.
+	NumberElement
+	// StringElement is a string, sorted byte by byte.
+	StringElement
+)
+
+// Element is a decoded element of a tuple key. Numbers are in the format DecodeToken returns.
+type Element struct {
+	Kind  ElementKind
+	Value string
+	// Descending is set for elements appended after KeyBuilder.Desc.
+	Descending bool
+}
+
+const (
+	tupleNullTag   byte = 0x01
+	tupleNumberTag byte = 0x02
+	tupleStringTag byte = 0x03
+	// The tags of descending elements are in the reverse order of the kinds.
+	tupleStringDescTag byte = 0x04
+	tupleNumberDescTag byte = 0x05
+	tupleNullDescTag   byte = 0x06
+	// tupleTerminator ends numbers and strings, it is lower than the tags so a longer element sorts after a
+	// shorter one.
+	tupleTerminator byte = 0x00
+	// tupleEscapedZero follows a zero byte of a string to tell it apart from the terminator.
+	tupleEscapedZero byte = 0xff
+)
+
+// KeyBuilder builds keys from tuples of numbers, strings and nulls, which sort in the lexicographic
+// order of the tuples: by the first element, then by the second one, and so on, with a shorter tuple
+// sorting before the longer ones that start with the same elements. Nulls sort before numbers, and
+// numbers before strings. Elements appended after calling Desc sort in the reverse order.
+//
+// Each element is tagged with its kind and delimited, and zero bytes of strings are escaped, so
+// elements can hold any value without confusing the order. The keys are binary and can be decoded with
+// DecodeKey.
+type KeyBuilder struct {
+	codec      Codec
+	key        []byte
+	ok         bool
+	descending bool
+}
+
+// Key starts building a tuple key, as in Key().Num("12.5").Str("abc").Null().Int(7).Bytes().
+func Key() *KeyBuilder {
+	return &KeyBuilder{ok: true}
+}
+
+// Desc makes the next element sort in descending order: higher numbers and later strings first, and
+// nulls last. The order of the other elements is not affected.
+func (k *KeyBuilder) Desc() *KeyBuilder {
+	k.descending = true
+	return k
+}
+
+// Num appends a number in any format EncodeToken accepts.
+func (k *KeyBuilder) Num(number string) *KeyBuilder {
+	var token string
+	var ok bool
+	if k.descending {
+		k.key = append(k.key, tupleNumberDescTag)
+		token, ok = k.codec.EncodeTokenDesc(number)
+	} else {
+		k.key = append(k.key, tupleNumberTag)
+		token, ok = k.codec.EncodeToken(number)
+	}
+	k.descending = false
+	if !ok || token == "" {
+		k.ok = false
+		return k
+	}
+	k.key = append(k.key, token...)
+	k.key = append(k.key, tupleTerminator)
+	return k
+}
+
+// Int appends an integer.
+func (k *KeyBuilder) Int(number int64) *KeyBuilder {
+	return k.Num(strconv.FormatInt(number, 10))
+}
+
+// Float appends a floating point number, which must not be infinite or NaN.
+func (k *KeyBuilder) Float(number float64) *KeyBuilder {
+	if math.IsInf(number, 0) || math.IsNaN(number) {
+		k.ok = false
+		k.descending = false
+		return k
+	}
+	return k.Num(strconv.FormatFloat(number, 'f', -1, 64))
+}
+
+// Time appends a point in time, as the number of seconds elapsed since the Unix epoch like EncodeTime.
+func (k *KeyBuilder) Time(t time.Time) *KeyBuilder {
+	return k.Num(formatSeconds(t.Unix(), int64(t.Nanosecond())))
+}
+
+// Str appends a string, which may contain any bytes.
+func (k *KeyBuilder) Str(text string) *KeyBuilder {
+	if k.descending {
+		k.key = append(k.key, tupleStringDescTag)
+		k.key = appendDescending(k.key, text)
+		k.descending = false
+		return k
+	}
+	k.key = append(k.key, tupleStringTag)
+	for i := 0; i < len(text); i++ {
+		k.key = append(k.key, text[i])
+		if text[i] == tupleTerminator {
+			k.key = append(k.key, tupleEscapedZero)
+		}
+	}
+	k.key = append(k.key, tupleTerminator)
+	return k
+}
+
+// Null appends a null element.
+func (k *KeyBuilder) Null() *KeyBuilder {
+	if k.descending {
+		k.key = append(k.key, tupleNullDescTag)
+	} else {
+		k.key = append(k.key, tupleNullTag)
+	}
+	k.descending = false
+	return k
+}
+
+// Bytes returns the key built so far, ok is false if any of the numbers could not be encoded.
+func (k *KeyBuilder) Bytes() (key []byte, ok bool) {
+	if !k.ok {
+		return nil, false
+	}
+	return append([]byte(nil), k.key...), true
+}
+
+// DecodeKey turns a key built by KeyBuilder back into its elements.
+func (c *Codec) DecodeKey(key []byte) (elements []Element, ok bool) {
+	for len(key) > 0 {
+		tag := key[0]
+		key = key[1:]
+		switch tag {
+		case tupleNullTag, tupleNullDescTag:
+			elements = append(elements, Element{Kind: NullElement, Descending: tag == tupleNullDescTag})
+		case tupleNumberTag, tupleNumberDescTag:
+			end := bytes.IndexByte(key, tupleTerminator)
+			if end <= 0 {
+				return nil, false
+			}
+			var number string
+			if tag == tupleNumberDescTag {
+				number, ok = c.DecodeTokenDesc(string(key[:end]))
+			} else {
+				number, ok = c.DecodeToken(string(key[:end]))
+			}
+			if !ok {
+				return nil, false
+			}
+			elements = append(elements, Element{Kind: NumberElement, Value: number, Descending: tag == tupleNumberDescTag})
+			key = key[end+1:]
+		case tupleStringTag, tupleStringDescTag:
+			text, rest, ok := decodeTupleString(key, tag == tupleStringDescTag)
+			if !ok {
+				return nil, false
+			}
+			elements = append(elements, Element{Kind: StringElement, Value: text, Descending: tag == tupleStringDescTag})
+			key = rest
+		default:
+			return nil, false
+		}
+	}
+	return elements, true
+}
+
+// decodeTupleString decodes a string element, inverting its bytes first if it is descending.
+func decodeTupleString(key []byte, descending bool) (text string, rest []byte, ok bool) {
+	var mask byte
+	if descending {
+		mask = 0xff
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(key); i++ {
+		if key[i]^mask != tupleTerminator {
+			b.WriteByte(key[i] ^ mask)
+			continue
+		}
+		if i+1 < len(key) && key[i+1]^mask == tupleEscapedZero {
+			b.WriteByte(tupleTerminator)
+			i++
+			continue
+		}
+		if descending {
+			if i+1 == len(key) || key[i+1]^mask != descendingTerminator {
+				return "", nil, false
+			}
+			i++
+		}
+		return b.String(), key[i+1:], true
+	}
+	return "", nil, false
+}
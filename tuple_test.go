@@ -0,0 +1,195 @@
+package conust
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestKeyBuilder(t *testing.T) {
+	key, ok := Key().Num("12.5").Str("abc").Null().Int(7).Bytes()
+	if !ok {
+		t.Fatal("building the key failed")
+	}
+	expected := []byte("\x0272125\x00\x03abc\x00\x01\x02717\x00")
+	if !bytes.Equal(key, expected) {
+		t.Fatalf("key expected %q got %q", expected, key)
+	}
+
+	elements, ok := new(Codec).DecodeKey(key)
+	if !ok {
+		t.Fatalf("decoding failed for %q", key)
+	}
+	expectedElements := []Element{
+		{Kind: NumberElement, Value: "12.5"},
+		{Kind: StringElement, Value: "abc"},
+		{Kind: NullElement},
+		{Kind: NumberElement, Value: "7"},
+	}
+	if !reflect.DeepEqual(elements, expectedElements) {
+		t.Fatalf("elements expected %v got %v", expectedElements, elements)
+	}
+}
+
+func TestKeyBuilder_RoundTrip(t *testing.T) {
+	key, ok := Key().Str("").Str("a\x00b\x00").Float(-0.25).Null().Null().Str("\xff").Bytes()
+	if !ok {
+		t.Fatal("building the key failed")
+	}
+	elements, ok := new(Codec).DecodeKey(key)
+	if !ok {
+		t.Fatalf("decoding failed for %q", key)
+	}
+	expected := []Element{
+		{Kind: StringElement, Value: ""},
+		{Kind: StringElement, Value: "a\x00b\x00"},
+		{Kind: NumberElement, Value: "-0.25"},
+		{Kind: NullElement},
+		{Kind: NullElement},
+		{Kind: StringElement, Value: "\xff"},
+	}
+	if !reflect.DeepEqual(elements, expected) {
+		t.Fatalf("elements expected %v got %v", expected, elements)
+	}
+}
+
+func TestKeyBuilder_Failure(t *testing.T) {
+	if _, ok := Key().Num("1.2.3").Bytes(); ok {
+		t.Fatal("invalid number should have failed")
+	}
+	if _, ok := Key().Num("").Bytes(); ok {
+		t.Fatal("empty number should have failed")
+	}
+	if _, ok := Key().Str("a").Float(math.NaN()).Bytes(); ok {
+		t.Fatal("NaN should have failed")
+	}
+
+	c := new(Codec)
+	for _, key := range []string{"\x04", "\x02711", "\x02\x00", "\x02x\x00", "\x03abc", "\x03a\x00\xfe"} {
+		if _, ok := c.DecodeKey([]byte(key)); ok {
+			t.Fatalf("decoding should have failed for %q", key)
+		}
+	}
+}
+
+func TestKeyBuilder_Order(t *testing.T) {
+	rand.Seed(42)
+	for i := 0; i < 20000; i++ {
+		a := randomTuple()
+		b := randomTuple()
+		keyA := buildTupleKey(t, a)
+		keyB := buildTupleKey(t, b)
+		expected := compareTuples(a, b)
+		if got := bytes.Compare(keyA, keyB); got != expected {
+			t.Fatalf("%v and %v compare as %d, expected %d (keys %q and %q)", a, b, got, expected, keyA, keyB)
+		}
+	}
+}
+
+func randomTuple() []Element {
+	elements := make([]Element, rand.Intn(4))
+	for i := range elements {
+		switch rand.Intn(3) {
+		case 0:
+			elements[i] = Element{Kind: NullElement}
+		case 1:
+			elements[i] = Element{Kind: NumberElement, Value: strconv.Itoa(rand.Intn(200) - 100)}
+			if rand.Intn(2) == 0 {
+				elements[i].Value += ".5"
+			}
+		default:
+			elements[i] = Element{Kind: StringElement, Value: randomString([]byte{0x00, 0x01, 0xff, 'a', 'b'}, 3)}
+		}
+	}
+	return elements
+}
+
+func buildTupleKey(t *testing.T, elements []Element) []byte {
+	k := Key()
+	for _, element := range elements {
+		switch element.Kind {
+		case NullElement:
+			k.Null()
+		case NumberElement:
+			k.Num(element.Value)
+		case StringElement:
+			k.Str(element.Value)
+		}
+	}
+	key, ok := k.Bytes()
+	if !ok {
+		t.Fatalf("building the key failed for %v", elements)
+	}
+	return key
+}
+
+func compareTuples(a []Element, b []Element) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].Kind != b[i].Kind {
+			return sign(int(a[i].Kind) - int(b[i].Kind))
+		}
+		var result int
+		switch a[i].Kind {
+		case NumberElement:
+			x, _ := new(big.Rat).SetString(a[i].Value)
+			y, _ := new(big.Rat).SetString(b[i].Value)
+			result = x.Cmp(y)
+		case StringElement:
+			result = compareStrings(a[i].Value, b[i].Value)
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return sign(len(a) - len(b))
+}
+
+func TestKeyBuilder_Desc(t *testing.T) {
+	key, ok := Key().Str("a").Desc().Int(12).Desc().Str("b\x00").Desc().Null().Int(1).Bytes()
+	if !ok {
+		t.Fatal("building the key failed")
+	}
+	elements, ok := new(Codec).DecodeKey(key)
+	if !ok {
+		t.Fatalf("decoding failed for %q", key)
+	}
+	expected := []Element{
+		{Kind: StringElement, Value: "a"},
+		{Kind: NumberElement, Value: "12", Descending: true},
+		{Kind: StringElement, Value: "b\x00", Descending: true},
+		{Kind: NullElement, Descending: true},
+		{Kind: NumberElement, Value: "1"},
+	}
+	if !reflect.DeepEqual(elements, expected) {
+		t.Fatalf("elements expected %v got %v", expected, elements)
+	}
+
+	ordered := [][]byte{
+		mustBuild(t, Key().Str("a").Desc().Str("b").Int(1)),
+		mustBuild(t, Key().Str("a").Desc().Str("a\x00")),
+		mustBuild(t, Key().Str("a").Desc().Str("a").Int(1)),
+		mustBuild(t, Key().Str("a").Desc().Str("a").Int(2)),
+		mustBuild(t, Key().Str("a").Desc().Int(120)),
+		mustBuild(t, Key().Str("a").Desc().Int(12)),
+		mustBuild(t, Key().Str("a").Desc().Int(-5)),
+		mustBuild(t, Key().Str("a").Desc().Null()),
+		mustBuild(t, Key().Str("b").Desc().Int(100)),
+	}
+	for i := 1; i < len(ordered); i++ {
+		if bytes.Compare(ordered[i-1], ordered[i]) >= 0 {
+			t.Fatalf("key %d (%q) does not sort before key %d (%q)", i-1, ordered[i-1], i, ordered[i])
+		}
+	}
+}
+
+func mustBuild(t *testing.T, k *KeyBuilder) []byte {
+	key, ok := k.Bytes()
+	if !ok {
+		t.Fatal("building the key failed")
+	}
+	return key
+}
@@ -0,0 +1,162 @@
+package conust
+
+import (
+	"strings"
+)
+
+// Bytes structuring the version keys. The token terminator is the in text separator, as in mixed text,
+// and the other markers are placed around it so that they order the parts of a version as expected.
+const versionComponentSeparator byte = '!'
+const versionPreReleaseMarker byte = '-'
+const versionReleaseMarker byte = '~'
+const versionNumericIdentifier byte = '1'
+const versionTextIdentifier byte = '2'
+
+// EncodeSemver turns a Semantic Versioning 2.0 version into a string that sorts by version precedence:
+// major, minor and patch compare numerically, a pre-release sorts before the release itself, numeric
+// pre-release identifiers compare by value and before alphanumeric ones, and a longer list of identifiers
+// sorts after its prefix. So "1.0.0-alpha" < "1.0.0-alpha.1" < "1.0.0-alpha.beta" < "1.0.0-rc.1" < "1.0.0".
+// Build metadata is validated but ignored. A leading "v" is accepted.
+// The output is a sort key only, it cannot be decoded.
+func (c *Codec) EncodeSemver(input string) (out string, ok bool) {
+	version := strings.TrimPrefix(input, "v")
+	if plus := strings.IndexByte(version, '+'); plus >= 0 {
+		if !isValidSemverIdentifiers(version[plus+1:], false) {
+			return "", false
+		}
+		version = version[:plus]
+	}
+
+	preRelease := ""
+	hasPreRelease := false
+	if dash := strings.IndexByte(version, '-'); dash >= 0 {
+		preRelease = version[dash+1:]
+		hasPreRelease = true
+		version = version[:dash]
+		if !isValidSemverIdentifiers(preRelease, true) {
+			return "", false
+		}
+	}
+
+	core := strings.Split(version, ".")
+	if len(core) != 3 {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.Grow(2*len(input) + 8)
+	for _, number := range core {
+		if !isSemverNumber(number) {
+			return "", false
+		}
+		c.writeVersionToken(&b, number)
+	}
+
+	if !hasPreRelease {
+		b.WriteByte(versionReleaseMarker)
+		return b.String(), true
+	}
+
+	b.WriteByte(versionPreReleaseMarker)
+	for _, identifier := range strings.Split(preRelease, ".") {
+		if isSemverNumber(identifier) {
+			b.WriteByte(versionNumericIdentifier)
+			c.writeVersionToken(&b, identifier)
+			continue
+		}
+		b.WriteByte(versionTextIdentifier)
+		b.WriteString(identifier)
+		b.WriteByte(inTextSeparator)
+	}
+	return b.String(), true
+}
+
+// EncodeVersion turns a loosely formatted dotted version like "2.4.10.3" or "1.2b" into a sortable string.
+// Each dot separated component is compared in turn, with its groups of decimal digits compared by
+// value, and a version sorts after its own prefix, so "1.2" < "1.2.0" < "1.10".
+// Components may only contain letters, digits, "-" and "_". A leading "v" is accepted.
+// Pre-release suffixes get no special treatment, use EncodeSemver for that.
+// The output is a sort key only, it cannot be decoded.
+func (c *Codec) EncodeVersion(input string) (out string, ok bool) {
+	version := strings.TrimPrefix(input, "v")
+	if version == "" {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.Grow(2*len(input) + 4)
+	for i, component := range strings.Split(version, ".") {
+		if !isValidVersionComponent(component) {
+			return "", false
+		}
+		if i > 0 {
+			b.WriteByte(versionComponentSeparator)
+		}
+		scanDecimalRuns(component, func(start int, end int, number bool) {
+			if number {
+				c.writeVersionToken(&b, component[start:end])
+			} else {
+				b.WriteString(component[start:end])
+			}
+		})
+	}
+	return b.String(), true
+}
+
+// writeVersionToken writes the token of a decimal number followed by the in text separator, which is
+// lower than anything that can come after it.
+func (c *Codec) writeVersionToken(b *strings.Builder, number string) {
+	encoded, _ := c.EncodeToken(number)
+	b.WriteString(encoded)
+	b.WriteByte(inTextSeparator)
+}
+
+func isSemverNumber(s string) bool {
+	if s == "" || (len(s) > 1 && s[0] == digit0) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDecimalDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidSemverIdentifiers validates a dot separated list of identifiers. Pre-release identifiers must
+// not be numbers with leading zeros, build metadata identifiers may be.
+func isValidSemverIdentifiers(s string, preRelease bool) bool {
+	for _, identifier := range strings.Split(s, ".") {
+		if identifier == "" {
+			return false
+		}
+		numeric := true
+		for i := 0; i < len(identifier); i++ {
+			ch := identifier[i]
+			if isDecimalDigit(ch) {
+				continue
+			}
+			numeric = false
+			if !isASCIILetter(ch) && ch != '-' {
+				return false
+			}
+		}
+		if preRelease && numeric && !isSemverNumber(identifier) {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidVersionComponent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if !isDecimalDigit(ch) && !isASCIILetter(ch) && ch != '-' && ch != '_' {
+			return false
+		}
+	}
+	return true
+}
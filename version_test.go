@@ -0,0 +1,123 @@
+package conust
+
+import (
+	"testing"
+)
+
+func TestEncodeSemver_Order(t *testing.T) {
+	// the precedence example of the SemVer 2.0 specification, extended
+	ordered := []string{
+		"0.9.99",
+		"1.0.0-0",
+		"1.0.0-9",
+		"1.0.0-10",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.9",
+		"1.0.0-alpha.10",
+		"1.0.0-alpha.beta",
+		"1.0.0-alpha-x",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.2.0",
+		"1.10.0",
+		"2.0.0-rc.1",
+		"2.0.0",
+		"10.0.0",
+	}
+
+	c := new(Codec)
+	prev, _ := c.EncodeSemver(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, ok := c.EncodeSemver(ordered[i])
+		if !ok {
+			t.Fatalf("encoding failed for %q", ordered[i])
+		}
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
+
+func TestEncodeSemver_BuildMetadata(t *testing.T) {
+	c := new(Codec)
+	plain, _ := c.EncodeSemver("1.0.0-rc.1")
+	for _, input := range []string{"1.0.0-rc.1+build.5", "v1.0.0-rc.1+001", "1.0.0-rc.1+exp.sha.5114f85"} {
+		encoded, ok := c.EncodeSemver(input)
+		if !ok {
+			t.Fatalf("encoding failed for %q", input)
+		}
+		if encoded != plain {
+			t.Fatalf("%q encoded as %q instead of %q", input, encoded, plain)
+		}
+	}
+}
+
+func TestEncodeSemver_Failure(t *testing.T) {
+	testCases := []string{
+		"",
+		"1",
+		"1.0",
+		"1.0.0.0",
+		"01.0.0",
+		"1.0.0-",
+		"1.0.0-01",
+		"1.0.0-alpha..1",
+		"1.0.0-al pha",
+		"1.0.0+",
+		"1.0.0+bu_ild",
+		"1.a.0",
+	}
+
+	c := new(Codec)
+	for _, input := range testCases {
+		if encoded, ok := c.EncodeSemver(input); ok || encoded != "" {
+			t.Fatalf("encoding should have failed for %q", input)
+		}
+	}
+}
+
+func TestEncodeVersion_Order(t *testing.T) {
+	ordered := []string{
+		"1",
+		"1.2",
+		"1.2.0",
+		"1.2.3",
+		"1.2.3.1",
+		"1.2.9",
+		"1.2.10",
+		"1.2b",
+		"1.10",
+		"2.4.9.3",
+		"2.4.10.3",
+		"v2.4.10.4",
+		"10",
+	}
+
+	c := new(Codec)
+	prev, _ := c.EncodeVersion(ordered[0])
+	for i := 1; i < len(ordered); i++ {
+		encoded, ok := c.EncodeVersion(ordered[i])
+		if !ok {
+			t.Fatalf("encoding failed for %q", ordered[i])
+		}
+		if prev >= encoded {
+			t.Fatalf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+		prev = encoded
+	}
+}
+
+func TestEncodeVersion_Failure(t *testing.T) {
+	c := new(Codec)
+	for _, input := range []string{"", "v", "1..2", "1.2.", "1.2 beta", "1.2+3"} {
+		if encoded, ok := c.EncodeVersion(input); ok || encoded != "" {
+			t.Fatalf("encoding should have failed for %q", input)
+		}
+	}
+}